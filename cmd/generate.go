@@ -3,7 +3,9 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
+	"go-scaffold/internal/config"
 	"go-scaffold/internal/generator"
 	"go-scaffold/internal/parser"
 
@@ -22,18 +24,21 @@ var generateCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		var schemaFiles []string
 
+		cfg, err := config.Load(config.DefaultFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Erreur de configuration: %v\n", err)
+			os.Exit(1)
+		}
+		cfg.Apply()
+
 		if generateAll {
 			// Générer pour tous les schémas dans database/schemas
-			files, err := os.ReadDir("database/schemas")
+			files, err := listSchemaFiles(schemasDir)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Erreur de lecture des schémas: %v\n", err)
 				os.Exit(1)
 			}
-			for _, file := range files {
-				if !file.IsDir() && (file.Name()[len(file.Name())-5:] == ".yaml" || file.Name()[len(file.Name())-4:] == ".yml") {
-					schemaFiles = append(schemaFiles, "database/schemas/"+file.Name())
-				}
-			}
+			schemaFiles = files
 		} else if len(args) > 0 {
 			schemaFiles = []string{args[0]}
 		} else {
@@ -46,8 +51,25 @@ var generateCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
+		// Schémas du projet, pour résoudre les relations entre tables
+		related, errs := loadSchemas(schemasDir)
+
+		// Les erreurs de parsing et les vérifications croisées (relations, actions
+		// référentielles) précèdent l'écriture du moindre fichier: un schéma écarté
+		// fausserait la validation des autres
+		if len(errs) == 0 {
+			errs = parser.ValidateSchemas(related)
+		}
+		if len(errs) > 0 {
+			for _, err := range errs {
+				fmt.Fprintf(os.Stderr, "✗ %v\n", err)
+			}
+			fmt.Fprintf(os.Stderr, "Génération annulée: %d erreur(s) de validation\n", len(errs))
+			os.Exit(1)
+		}
+
 		for _, schemaFile := range schemaFiles {
-			if err := generateFromSchema(schemaFile); err != nil {
+			if err := generateFromSchema(schemaFile, cfg, related); err != nil {
 				fmt.Fprintf(os.Stderr, "Erreur lors de la génération de %s: %v\n", schemaFile, err)
 				continue
 			}
@@ -60,15 +82,75 @@ func init() {
 	generateCmd.Flags().BoolVarP(&generateAll, "all", "a", false, "Générer pour tous les schémas")
 }
 
-func generateFromSchema(schemaFile string) error {
+// schemasDir est le répertoire des schémas d'un projet
+const schemasDir = "database/schemas"
+
+// listSchemaFiles retourne les fichiers de schéma YAML d'un répertoire
+func listSchemaFiles(dir string) ([]string, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var schemaFiles []string
+	for _, file := range files {
+		ext := filepath.Ext(file.Name())
+		if !file.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			schemaFiles = append(schemaFiles, filepath.Join(dir, file.Name()))
+		}
+	}
+	return schemaFiles, nil
+}
+
+// schemaFileError est l'erreur de parsing d'un fichier de schéma
+type schemaFileError struct {
+	File string
+	Err  error
+}
+
+func (e *schemaFileError) Error() string {
+	return e.File + ": " + e.Err.Error()
+}
+
+func (e *schemaFileError) Unwrap() error {
+	return e.Err
+}
+
+// loadSchemas charge les schémas d'un répertoire et retourne, à part, les erreurs de
+// parsing des fichiers invalides (*schemaFileError)
+func loadSchemas(dir string) ([]*parser.Schema, []error) {
+	files, err := listSchemaFiles(dir)
+	if err != nil {
+		return nil, nil
+	}
+
+	var schemas []*parser.Schema
+	var errs []error
+	for _, file := range files {
+		schema, err := parser.ParseSchema(file)
+		if err != nil {
+			errs = append(errs, &schemaFileError{File: file, Err: err})
+			continue
+		}
+		schemas = append(schemas, schema)
+	}
+	for _, schema := range schemas {
+		parser.ResolveMorphColumns(schema, schemas)
+	}
+	return schemas, errs
+}
+
+func generateFromSchema(schemaFile string, cfg *config.Config, related []*parser.Schema) error {
 	// Parser le schéma
 	schema, err := parser.ParseSchema(schemaFile)
 	if err != nil {
 		return fmt.Errorf("erreur de parsing du schéma: %w", err)
 	}
+	parser.ResolveMorphColumns(schema, related)
 
 	// Créer le générateur
-	gen := generator.NewGenerator(schema)
+	gen := generator.NewGenerator(schema, cfg)
+	gen.Schemas = related
 
 	// Générer le model
 	if err := gen.GenerateModel(); err != nil {
@@ -95,5 +177,15 @@ func generateFromSchema(schemaFile string) error {
 		return fmt.Errorf("erreur de génération des routes: %w", err)
 	}
 
+	// Générer le conteneur de dépendances
+	if err := gen.GenerateContainer(); err != nil {
+		return fmt.Errorf("erreur de génération du conteneur: %w", err)
+	}
+
+	// Générer le script SQL de la table
+	if err := gen.GenerateMigration(); err != nil {
+		return fmt.Errorf("erreur de génération de la migration: %w", err)
+	}
+
 	return nil
 }
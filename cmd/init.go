@@ -57,11 +57,14 @@ func createBaseFiles(projectName string) error {
 	// go.mod
 	goModContent := fmt.Sprintf(`module %s
 
-go 1.21
+go 1.22
 
 require (
 	github.com/gin-gonic/gin v1.9.1
 	github.com/go-playground/validator/v10 v10.16.0
+	github.com/google/uuid v1.6.0
+	github.com/oklog/ulid/v2 v2.1.0
+	github.com/shopspring/decimal v1.3.1
 	gorm.io/gorm v1.25.5
 	gorm.io/driver/postgres v1.5.4
 )
@@ -217,6 +220,33 @@ func RegisterRoutes(router *gin.Engine) {
 		return err
 	}
 
+	// go-scaffold.yaml
+	scaffoldConfigContent := `# Configuration de go-scaffold
+dialect: postgres
+
+# Colonnes nullables: pointer, sql ou generic
+nullable: pointer
+
+# Types de colonnes personnalisés (en plus de email, url, phone et ip)
+types: {}
+
+# Signatures des repositories: context ou legacy (sans context.Context)
+repositories: context
+
+# Colonne de tenant des schémas tenant_scoped: true
+tenant_column: tenant_id
+
+# Règles de nommage propres au projet (pluriels, sigles)
+inflections:
+  irregular: {}
+  uncountable: []
+  initialisms: []
+`
+
+	if err := os.WriteFile(filepath.Join(projectName, "go-scaffold.yaml"), []byte(scaffoldConfigContent), 0644); err != nil {
+		return err
+	}
+
 	// .env.example
 	envContent := `DB_HOST=localhost
 DB_PORT=5432
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
+
+	"go-scaffold/internal/config"
+	"go-scaffold/internal/inflection"
 
 	"github.com/spf13/cobra"
 )
@@ -42,14 +44,39 @@ var makeMigrationCmd = &cobra.Command{
 	},
 }
 
+var makeObserverCmd = &cobra.Command{
+	Use:   "observer [model]",
+	Short: "Créer un observer des événements d'un model",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		filename, err := createObserver(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Erreur: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Observer '%s' créé avec succès!\n", filename)
+		fmt.Println("  Activez events: true dans le schéma du model et importez le package dans main.go:")
+		fmt.Println("  _ \"<module>/app/observers\"")
+	},
+}
+
 func init() {
 	makeCmd.AddCommand(makeSchemaCmd)
 	makeCmd.AddCommand(makeMigrationCmd)
+	makeCmd.AddCommand(makeObserverCmd)
 }
 
 func createSchema(name string) error {
+	// Les règles de pluralisation du projet s'appliquent au nom de la table
+	cfg, err := config.Load(config.DefaultFile)
+	if err != nil {
+		return err
+	}
+	cfg.Apply()
+
 	// Normaliser le nom (singulier, snake_case)
-	schemaName := toSnakeCase(name)
+	schemaName := inflection.Snake(inflection.Singularize(name))
+	tableName := inflection.Pluralize(schemaName)
 	filename := filepath.Join("database", "schemas", schemaName+".yaml")
 
 	// Vérifier si le fichier existe déjà
@@ -63,9 +90,9 @@ func createSchema(name string) error {
 	}
 
 	// Template de schéma
-	template := `# Schéma pour la table ` + schemaName + `
-table: ` + schemaName + `
-model: ` + toPascalCase(name) + `
+	template := `# Schéma pour la table ` + tableName + `
+table: ` + tableName + `
+model: ` + inflection.Pascal(schemaName) + `
 
 # Définir les colonnes de la table
 columns:
@@ -121,7 +148,7 @@ relations:
 
 # Indexes (optionnel)
 indexes:
-  - name: idx_` + schemaName + `_status
+  - name: idx_` + tableName + `_status
     columns: [status]
     unique: false
 
@@ -154,7 +181,7 @@ func createMigration(name string) error {
 
 	// Générer un nom de fichier avec timestamp
 	timestamp := fmt.Sprintf("%d", os.Getpid()) // Simplification pour l'exemple
-	filename := filepath.Join("database", "migrations", fmt.Sprintf("%s_%s.go", timestamp, toSnakeCase(name)))
+	filename := filepath.Join("database", "migrations", fmt.Sprintf("%s_%s.go", timestamp, inflection.Snake(name)))
 
 	template := `package migrations
 
@@ -186,23 +213,62 @@ func init() {
 	return os.WriteFile(filename, []byte(template), 0644)
 }
 
-func toSnakeCase(s string) string {
-	var result strings.Builder
-	for i, r := range s {
-		if i > 0 && r >= 'A' && r <= 'Z' {
-			result.WriteRune('_')
-		}
-		result.WriteRune(r)
+func createObserver(name string) (string, error) {
+	cfg, err := config.Load(config.DefaultFile)
+	if err != nil {
+		return "", err
 	}
-	return strings.ToLower(result.String())
-}
+	cfg.Apply()
 
-func toPascalCase(s string) string {
-	words := strings.FieldsFunc(s, func(r rune) bool {
-		return r == '_' || r == '-' || r == ' '
-	})
-	for i, word := range words {
-		words[i] = strings.Title(strings.ToLower(word))
+	modelName := inflection.Pascal(inflection.Singularize(name))
+	observerName := modelName + "Observer"
+	filename := filepath.Join("app", "observers", inflection.Snake(observerName)+".go")
+
+	if _, err := os.Stat(filename); err == nil {
+		return "", fmt.Errorf("l'observer %s existe déjà", filename)
 	}
-	return strings.Join(words, "")
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return "", err
+	}
+
+	template := `package observers
+
+import (
+	"context"
+
+	"app/events"
+	"app/models"
+)
+
+// ` + observerName + ` réagit aux événements du model ` + modelName + `
+type ` + observerName + ` struct{}
+
+func init() {
+	observer := &` + observerName + `{}
+	events.Listen(observer.Created)
+	events.Listen(observer.Updated)
+	events.Listen(observer.Deleted)
+}
+
+// Created est appelé après la création d'un ` + modelName + `
+func (o *` + observerName + `) Created(ctx context.Context, event models.` + modelName + `Created) error {
+	// Votre code ici
+	return nil
+}
+
+// Updated est appelé après la mise à jour d'un ` + modelName + ` (event.Changed liste les colonnes écrites)
+func (o *` + observerName + `) Updated(ctx context.Context, event models.` + modelName + `Updated) error {
+	// Votre code ici
+	return nil
+}
+
+// Deleted est appelé après la suppression d'un ` + modelName + `
+func (o *` + observerName + `) Deleted(ctx context.Context, event models.` + modelName + `Deleted) error {
+	// Votre code ici
+	return nil
+}
+`
+
+	return filename, os.WriteFile(filename, []byte(template), 0644)
 }
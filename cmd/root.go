@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"os"
+	"strings"
+
 	"github.com/spf13/cobra"
 )
 
@@ -12,6 +15,12 @@ var rootCmd = &cobra.Command{
 }
 
 func Execute() error {
+	// Syntaxe à la Laravel: make:observer équivaut à make observer
+	if len(os.Args) > 1 {
+		if parent, sub, ok := strings.Cut(os.Args[1], ":"); ok {
+			rootCmd.SetArgs(append([]string{parent, sub}, os.Args[2:]...))
+		}
+	}
 	return rootCmd.Execute()
 }
 
@@ -19,4 +28,5 @@ func init() {
 	rootCmd.AddCommand(generateCmd)
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(makeCmd)
+	rootCmd.AddCommand(schemaCmd)
 }
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go-scaffold/internal/config"
+	"go-scaffold/internal/parser"
+
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Outils pour les fichiers de schéma",
+}
+
+var schemaValidateCmd = &cobra.Command{
+	Use:   "validate [chemin-schema]",
+	Short: "Valider un schéma, ou tous les schémas de database/schemas",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Load(config.DefaultFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Erreur de configuration: %v\n", err)
+			os.Exit(1)
+		}
+		cfg.Apply()
+
+		if err := validateSchemas(args); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaValidateCmd)
+}
+
+func validateSchemas(args []string) error {
+	files := args
+	if len(files) == 0 {
+		var err error
+		if files, err = listSchemaFiles(schemasDir); err != nil {
+			return fmt.Errorf("erreur de lecture des schémas: %w", err)
+		}
+	}
+
+	failed := 0
+	var schemas []*parser.Schema
+	checked := map[string]bool{}
+	for _, file := range files {
+		checked[filepath.Clean(file)] = true
+		schema, err := parser.ParseSchema(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "✗ %s: %v\n", file, err)
+			failed++
+			continue
+		}
+		schemas = append(schemas, schema)
+	}
+
+	// Les relations sont vérifiées avec l'ensemble des schémas du projet
+	related := schemas
+	if len(args) > 0 {
+		seen := map[string]bool{}
+		for _, schema := range schemas {
+			seen[schema.Model] = true
+		}
+		others, errs := loadSchemas(schemasDir)
+		for _, err := range errs {
+			var fileErr *schemaFileError
+			if errors.As(err, &fileErr) && checked[filepath.Clean(fileErr.File)] {
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "✗ %v\n", err)
+			failed++
+		}
+		for _, schema := range others {
+			if !seen[schema.Model] {
+				related = append(related, schema)
+			}
+		}
+	}
+	// Un schéma écarté rendrait inconnus les models qu'il déclare: les relations ne
+	// sont vérifiées que si tous les schémas sont lisibles
+	if failed > 0 {
+		return fmt.Errorf("%d erreur(s) de validation", failed)
+	}
+	for _, err := range parser.ValidateSchemas(related) {
+		fmt.Fprintf(os.Stderr, "✗ %v\n", err)
+		failed++
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d erreur(s) de validation", failed)
+	}
+	fmt.Printf("✓ %d schéma(s) valide(s)\n", len(schemas))
+	return nil
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"go-scaffold/internal/inflection"
+	"go-scaffold/internal/parser"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile est le nom du fichier de configuration à la racine du projet
+const DefaultFile = "go-scaffold.yaml"
+
+// Signatures des méthodes de repository générées
+const (
+	RepositoriesContext = "context" // ctx context.Context en premier paramètre
+	RepositoriesLegacy  = "legacy"  // anciennes signatures, sans contexte
+)
+
+// Config représente la configuration d'un projet généré
+type Config struct {
+	Dialect      string                           `yaml:"dialect"`
+	Nullable     string                           `yaml:"nullable"` // pointer, sql ou generic
+	Types        map[string]parser.TypeDefinition `yaml:"types"`
+	Inflections  Inflections                      `yaml:"inflections"`
+	TenantColumn string                           `yaml:"tenant_column"` // Colonne de tenant des schémas tenant_scoped
+	Repositories string                           `yaml:"repositories"`  // context ou legacy
+}
+
+// Inflections complète les règles de nommage du projet
+type Inflections struct {
+	Irregular   map[string]string `yaml:"irregular"`   // Pluriels irréguliers (singulier: pluriel)
+	Uncountable []string          `yaml:"uncountable"` // Mots invariables
+	Initialisms []string          `yaml:"initialisms"` // Sigles écrits en majuscules (ex: SKU)
+}
+
+// Default retourne la configuration par défaut
+func Default() *Config {
+	return &Config{
+		Dialect:      parser.DialectPostgres,
+		Nullable:     parser.NullablePointer,
+		Repositories: RepositoriesContext,
+	}
+}
+
+// Load lit le fichier de configuration du projet.
+// Un fichier absent n'est pas une erreur: la configuration par défaut est utilisée.
+func Load(filename string) (*Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("impossible de lire la configuration: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("erreur de parsing de %s: %w", filename, err)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Apply transmet au parser le dialecte, les types personnalisés et le style des colonnes nullables,
+// et enregistre les règles de nommage du projet
+func (c *Config) Apply() {
+	parser.SetDialect(c.Dialect)
+	parser.SetNullableStyle(c.Nullable)
+	parser.SetTenantColumn(c.TenantColumn)
+	for name, def := range c.Types {
+		parser.RegisterType(name, def)
+	}
+
+	for singular, plural := range c.Inflections.Irregular {
+		inflection.AddIrregular(singular, plural)
+	}
+	inflection.AddUncountable(c.Inflections.Uncountable...)
+	inflection.AddInitialism(c.Inflections.Initialisms...)
+}
+
+// validate valide la configuration
+func (c *Config) validate() error {
+	switch c.Dialect {
+	case parser.DialectPostgres, parser.DialectMySQL, parser.DialectSQLite:
+	default:
+		return fmt.Errorf("dialecte non supporté: %s", c.Dialect)
+	}
+
+	switch c.Nullable {
+	case parser.NullablePointer, parser.NullableSQL, parser.NullableGeneric:
+	default:
+		return fmt.Errorf("style nullable non supporté: %s", c.Nullable)
+	}
+
+	switch c.Repositories {
+	case RepositoriesContext, RepositoriesLegacy:
+	default:
+		return fmt.Errorf("signatures de repository non supportées: %s", c.Repositories)
+	}
+
+	for name, def := range c.Types {
+		if def.GoType == "" {
+			return fmt.Errorf("le type personnalisé %s doit définir go_type", name)
+		}
+		if def.JSON != "" && def.JSON != "string" {
+			return fmt.Errorf("représentation JSON inconnue pour le type %s: %s", name, def.JSON)
+		}
+	}
+
+	return nil
+}
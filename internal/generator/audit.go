@@ -0,0 +1,413 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go-scaffold/internal/inflection"
+	"go-scaffold/internal/parser"
+)
+
+// Les schémas audit: true enregistrent chaque création, mise à jour et suppression faite
+// par le repository (ainsi que la restauration et la suppression définitive des schémas
+// soft_deletes) dans la table audits (anciennes et nouvelles valeurs, utilisateur
+// du contexte de la requête). L'historique est exposé sur GET /<ressource>/:id/history.
+
+// auditSchema décrit la table audits partagée par les schémas audités
+func auditSchema() *parser.Schema {
+	return &parser.Schema{
+		Table: "audits",
+		Model: "Entry",
+		Columns: []parser.Column{
+			{Name: "id", Type: "bigint", Primary: true, AutoIncrement: true},
+			{Name: "auditable_type", Type: "string", Size: 100},
+			{Name: "auditable_id", Type: "string", Size: 100},
+			{Name: "action", Type: "string", Size: 20},
+			{Name: "user_id", Type: "string", Size: 100, Nullable: true},
+			{Name: "old_values", Type: "text", Nullable: true},
+			{Name: "new_values", Type: "text", Nullable: true},
+			{Name: "created_at", Type: "timestamp"},
+		},
+		Indexes: []parser.Index{
+			{Name: "idx_audits_auditable", Columns: []string{"auditable_type", "auditable_id"}},
+		},
+	}
+}
+
+// GenerateAudit génère le package app/audit et le script SQL de la table audits
+func (g *Generator) GenerateAudit() error {
+	filename := filepath.Join("app", "audit", "audit.go")
+
+	// Créer le répertoire s'il n'existe pas
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filename, []byte(auditPackageContent), 0644); err != nil {
+		return err
+	}
+
+	table := &Generator{Schema: auditSchema(), Config: g.Config}
+	return table.GenerateMigration()
+}
+
+// auditKey retourne l'identifiant audité construit à partir des arguments de clé
+func auditKey(args string) string {
+	return "audit.Key(" + args + ")"
+}
+
+// modelKeyArgs retourne les champs de clé primaire d'une variable model (ex: "article.ID")
+func (g *Generator) modelKeyArgs(varName string) string {
+	var args []string
+	for _, col := range g.Schema.PrimaryKeys() {
+		args = append(args, varName+"."+inflection.Pascal(col.Name))
+	}
+	return strings.Join(args, ", ")
+}
+
+// writeAuditRepositoryInterface écrit les méthodes d'audit dans l'interface du repository
+func (g *Generator) writeAuditRepositoryInterface(sb *strings.Builder) {
+	sb.WriteString(fmt.Sprintf("\tHistory(%s) ([]audit.Entry, error)\n", g.ctxParams(g.keyParams())))
+}
+
+// writeAuditCreate écrit le corps de Create: insertion et entrée d'audit dans une transaction
+func (g *Generator) writeAuditCreate(sb *strings.Builder) {
+	varName := inflection.Camel(g.Schema.Model)
+	sb.WriteString(fmt.Sprintf("\treturn %s.Transaction(func(tx *gorm.DB) error {\n", g.repoDB()))
+	sb.WriteString(fmt.Sprintf("\t\tif err := tx.Create(%s).Error; err != nil {\n", varName))
+	sb.WriteString("\t\t\treturn err\n")
+	sb.WriteString("\t\t}\n")
+	sb.WriteString(fmt.Sprintf("\t\treturn audit.Record(tx, %q, %s, audit.Created, nil, %s)\n",
+		g.Schema.Model, auditKey(g.modelKeyArgs(varName)), varName))
+	sb.WriteString("\t})\n")
+}
+
+// writeAuditUpdate écrit la fin de Update: relecture des anciennes valeurs, mise à jour
+// et entrée d'audit des seules colonnes modifiées, dans une transaction
+func (g *Generator) writeAuditUpdate(sb *strings.Builder) {
+	modelName := g.Schema.Model
+	varName := inflection.Camel(modelName)
+	keyArgs := g.modelKeyArgs(varName)
+	if g.Schema.Versioned {
+		sb.WriteString(fmt.Sprintf("\terr := %s.Transaction(func(tx *gorm.DB) error {\n", g.repoDB()))
+	} else {
+		sb.WriteString(fmt.Sprintf("\treturn %s.Transaction(func(tx *gorm.DB) error {\n", g.repoDB()))
+	}
+	sb.WriteString(fmt.Sprintf("\t\tvar before models.%s\n", modelName))
+	sb.WriteString(fmt.Sprintf("\t\tif err := tx.Where(\"%s\", %s).First(&before).Error; err != nil {\n", g.keyWhere(), keyArgs))
+	sb.WriteString("\t\t\treturn err\n")
+	sb.WriteString("\t\t}\n")
+	if g.Schema.Versioned {
+		g.writeVersionedStatement(sb, "tx", "\t\t")
+	} else {
+		sb.WriteString(fmt.Sprintf("\t\tif err := tx.Model(%s).Select(fields).Updates(%s).Error; err != nil {\n", varName, varName))
+		sb.WriteString("\t\t\treturn err\n")
+		sb.WriteString("\t\t}\n")
+	}
+	sb.WriteString(fmt.Sprintf("\t\treturn audit.Record(tx, %q, %s, audit.Updated, &before, %s, fields...)\n",
+		modelName, auditKey(keyArgs), varName))
+	sb.WriteString("\t})\n")
+	if g.Schema.Versioned {
+		g.writeVersionRollback(sb, varName)
+	}
+}
+
+// writeAuditDelete écrit le corps de Delete: l'enregistrement est chargé pour conserver
+// ses dernières valeurs (et les transmettre à l'événement Deleted le cas échéant)
+func (g *Generator) writeAuditDelete(sb *strings.Builder) {
+	g.writeAuditRemoval(sb, "tx", "audit.Deleted", "nil", g.Schema.Versioned)
+}
+
+// writeAuditForceDelete écrit le corps de ForceDelete: comme Delete, y compris pour un
+// enregistrement de la corbeille
+func (g *Generator) writeAuditForceDelete(sb *strings.Builder) {
+	g.writeAuditRemoval(sb, "tx.Unscoped()", "audit.ForceDeleted", g.softDeleteMissingError(), false)
+}
+
+// writeAuditRemoval écrit la suppression par db (tx ou tx.Unscoped()) et son entrée
+// d'audit action, dans une transaction; missing est renvoyé si l'enregistrement n'existe pas
+// et versioned conditionne la suppression par la version attendue de Delete
+func (g *Generator) writeAuditRemoval(sb *strings.Builder, db, action, missing string, versioned bool) {
+	modelName := g.Schema.Model
+	varName := inflection.Camel(modelName)
+	sb.WriteString(fmt.Sprintf("\treturn %s.Transaction(func(tx *gorm.DB) error {\n", g.repoDB()))
+	sb.WriteString(fmt.Sprintf("\t\tvar %s models.%s\n", varName, modelName))
+	sb.WriteString(fmt.Sprintf("\t\tif err := %s.Where(\"%s\", %s).First(&%s).Error; err != nil {\n", db, g.keyWhere(), g.keyArgs(), varName))
+	sb.WriteString("\t\t\tif errors.Is(err, gorm.ErrRecordNotFound) {\n")
+	sb.WriteString(fmt.Sprintf("\t\t\t\treturn %s\n", missing))
+	sb.WriteString("\t\t\t}\n")
+	sb.WriteString("\t\t\treturn err\n")
+	sb.WriteString("\t\t}\n")
+	// Les anciennes valeurs sont copiées avant la suppression, qui renseigne deleted_at
+	sb.WriteString(fmt.Sprintf("\t\tbefore := %s\n", varName))
+	if versioned {
+		g.writeVersionedDelete(sb, db, "&"+varName, "\t\t")
+	} else {
+		sb.WriteString(fmt.Sprintf("\t\tif err := %s.Delete(&%s).Error; err != nil {\n", db, varName))
+		sb.WriteString("\t\t\treturn err\n")
+		sb.WriteString("\t\t}\n")
+	}
+	sb.WriteString(fmt.Sprintf("\t\treturn audit.Record(tx, %q, %s, %s, &before, nil)\n",
+		modelName, auditKey(g.keyArgs()), action))
+	sb.WriteString("\t})\n")
+}
+
+// writeAuditRestore écrit le corps de Restore: l'enregistrement de la corbeille est
+// chargé, restauré et l'effacement de deleted_at enregistré, dans une transaction
+func (g *Generator) writeAuditRestore(sb *strings.Builder) {
+	modelName := g.Schema.Model
+	varName := inflection.Camel(modelName)
+	sb.WriteString(fmt.Sprintf("\treturn %s.Transaction(func(tx *gorm.DB) error {\n", g.repoDB()))
+	sb.WriteString(fmt.Sprintf("\t\tvar %s models.%s\n", varName, modelName))
+	sb.WriteString(fmt.Sprintf("\t\tif err := tx.Unscoped().Where(\"%s AND deleted_at IS NOT NULL\", %s).First(&%s).Error; err != nil {\n", g.keyWhere(), g.keyArgs(), varName))
+	sb.WriteString("\t\t\tif errors.Is(err, gorm.ErrRecordNotFound) {\n")
+	sb.WriteString(fmt.Sprintf("\t\t\t\treturn %s\n", g.softDeleteMissingError()))
+	sb.WriteString("\t\t\t}\n")
+	sb.WriteString("\t\t\treturn err\n")
+	sb.WriteString("\t\t}\n")
+	sb.WriteString(fmt.Sprintf("\t\tbefore := %s\n", varName))
+	sb.WriteString(fmt.Sprintf("\t\tif err := tx.Unscoped().Model(&%s).Select(\"deleted_at\").Update(\"deleted_at\", nil).Error; err != nil {\n", varName))
+	sb.WriteString("\t\t\treturn err\n")
+	sb.WriteString("\t\t}\n")
+	sb.WriteString(fmt.Sprintf("\t\t%s.DeletedAt = gorm.DeletedAt{}\n", varName))
+	sb.WriteString(fmt.Sprintf("\t\treturn audit.Record(tx, %q, %s, audit.Restored, &before, &%s, \"deleted_at\")\n",
+		modelName, auditKey(g.keyArgs()), varName))
+	sb.WriteString("\t})\n")
+}
+
+// writeAuditRepository écrit la méthode History
+func (g *Generator) writeAuditRepository(sb *strings.Builder) {
+	modelName := g.Schema.Model
+	repoName := modelName + "Repository"
+	varName := inflection.Camel(modelName)
+
+	sb.WriteString(fmt.Sprintf("// History retourne l'historique des modifications d'un %s, du plus récent au plus ancien\n", varName))
+	sb.WriteString(fmt.Sprintf("func (r *%s) History(%s) ([]audit.Entry, error) {\n", repoName, g.ctxParams(g.keyParams())))
+	sb.WriteString("\tvar entries []audit.Entry\n")
+	sb.WriteString(fmt.Sprintf("\terr := %s.\n", g.repoDB()))
+	sb.WriteString(fmt.Sprintf("\t\tWhere(\"auditable_type = ? AND auditable_id = ?\", %q, %s).\n", modelName, auditKey(g.keyArgs())))
+	sb.WriteString("\t\tOrder(\"id DESC\").\n")
+	sb.WriteString("\t\tFind(&entries).Error\n")
+	sb.WriteString("\treturn entries, err\n")
+	sb.WriteString("}\n\n")
+}
+
+// writeAuditHandler écrit l'endpoint d'historique
+func (g *Generator) writeAuditHandler(sb *strings.Builder) {
+	modelName := g.Schema.Model
+	controllerName := modelName + "Controller"
+	varName := inflection.Camel(modelName)
+
+	sb.WriteString(fmt.Sprintf("// History récupère l'historique des modifications d'un %s\n", varName))
+	sb.WriteString(fmt.Sprintf("// @Summary Historique d'un %s\n", varName))
+	sb.WriteString("// @Description Récupère les créations, mises à jour et suppressions d'un " + varName + ", du plus récent au plus ancien\n")
+	sb.WriteString("// @Tags " + modelName + "\n")
+	sb.WriteString("// @Accept json\n")
+	sb.WriteString("// @Produce json\n")
+	g.writeKeyDocParams(sb)
+	sb.WriteString("// @Success 200 {object} map[string]interface{}\n")
+	sb.WriteString("// @Router /" + g.resourceName() + g.keyDocRoute() + "/history [get]\n")
+	sb.WriteString(fmt.Sprintf("func (ctrl *%s) History(c *gin.Context) {\n", controllerName))
+	g.writeKeyParsing(sb)
+	if g.Schema.TenantScoped {
+		g.writeAuditExistence(sb)
+	}
+	sb.WriteString(fmt.Sprintf("\tentries, err := %s\n", g.repoCall("History", g.keyArgs())))
+	sb.WriteString("\tif err != nil {\n")
+	sb.WriteString("\t\tc.JSON(http.StatusInternalServerError, gin.H{\n")
+	sb.WriteString("\t\t\t\"error\": \"Erreur lors de la récupération des données\",\n")
+	sb.WriteString("\t\t})\n")
+	sb.WriteString("\t\treturn\n")
+	sb.WriteString("\t}\n\n")
+	sb.WriteString("\tc.JSON(http.StatusOK, gin.H{\n")
+	sb.WriteString("\t\t\"data\": entries,\n")
+	sb.WriteString("\t})\n")
+	sb.WriteString("}\n\n")
+}
+
+// writeAuditExistence écrit la vérification que l'enregistrement appartient au tenant de
+// la requête; un enregistrement de la corbeille garde son historique
+func (g *Generator) writeAuditExistence(sb *strings.Builder) {
+	if !g.Schema.SoftDeletes {
+		g.writeTenantExistence(sb)
+		return
+	}
+	unscoped := "func(db *gorm.DB) *gorm.DB { return db.Unscoped() }"
+	sb.WriteString(fmt.Sprintf("\tif _, err := %s; err != nil {\n", g.repoCall("FindByID", g.keyArgs()+", "+unscoped)))
+	sb.WriteString("\t\tc.JSON(http.StatusNotFound, gin.H{\n")
+	sb.WriteString("\t\t\t\"error\": \"Enregistrement non trouvé\",\n")
+	sb.WriteString("\t\t})\n")
+	sb.WriteString("\t\treturn\n")
+	sb.WriteString("\t}\n\n")
+}
+
+// writeAuditRoutes écrit la route d'historique
+func (g *Generator) writeAuditRoutes(sb *strings.Builder) {
+	varName := inflection.Camel(g.Schema.Model)
+	path := g.keyRoute() + "/history"
+	sb.WriteString(fmt.Sprintf("\t\t%sGroup.GET(\"%s\", ctrl.History) // GET /%s%s\n",
+		varName, path, g.resourceName(), path))
+}
+
+// auditPackageContent contient le package d'audit des projets générés
+const auditPackageContent = `package audit
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Actions enregistrées
+const (
+	Created      = "created"
+	Updated      = "updated"
+	Deleted      = "deleted"
+	Restored     = "restored"      // Sortie de la corbeille (soft_deletes)
+	ForceDeleted = "force_deleted" // Suppression définitive (soft_deletes)
+)
+
+// Values contient des valeurs de colonnes, stockées en JSON
+type Values map[string]interface{}
+
+// Value implémente driver.Valuer
+func (v Values) Value() (driver.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+// Scan implémente sql.Scanner
+func (v *Values) Scan(src interface{}) error {
+	switch data := src.(type) {
+	case nil:
+		*v = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(data), v)
+	case []byte:
+		return json.Unmarshal(data, v)
+	}
+	return fmt.Errorf("audit: type %T non supporté", src)
+}
+
+// Entry représente une ligne de la table audits
+type Entry struct {
+	ID            int64     ` + "`" + `json:"id" gorm:"primaryKey;autoIncrement;column:id"` + "`" + `
+	AuditableType string    ` + "`" + `json:"auditable_type" gorm:"size:100;not null;column:auditable_type"` + "`" + `
+	AuditableID   string    ` + "`" + `json:"auditable_id" gorm:"size:100;not null;column:auditable_id"` + "`" + `
+	Action        string    ` + "`" + `json:"action" gorm:"size:20;not null;column:action"` + "`" + `
+	UserID        *string   ` + "`" + `json:"user_id" gorm:"size:100;column:user_id"` + "`" + `
+	OldValues     Values    ` + "`" + `json:"old_values" gorm:"type:text;column:old_values"` + "`" + `
+	NewValues     Values    ` + "`" + `json:"new_values" gorm:"type:text;column:new_values"` + "`" + `
+	CreatedAt     time.Time ` + "`" + `json:"created_at" gorm:"autoCreateTime;column:created_at"` + "`" + `
+}
+
+// TableName retourne le nom de la table
+func (Entry) TableName() string {
+	return "audits"
+}
+
+type userKey struct{}
+
+// WithUser retourne un contexte portant l'utilisateur à l'origine des modifications
+func WithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// UserFrom retourne l'utilisateur porté par le contexte
+func UserFrom(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	user, ok := ctx.Value(userKey{}).(string)
+	return user, ok && user != ""
+}
+
+// Middleware renseigne l'utilisateur de l'audit dans le contexte de la requête,
+// à partir de resolve (ex: identifiant extrait du jeton d'authentification)
+func Middleware(resolve func(c *gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if user := resolve(c); user != "" {
+			c.Request = c.Request.WithContext(WithUser(c.Request.Context(), user))
+		}
+		c.Next()
+	}
+}
+
+// Key retourne l'identifiant audité d'un enregistrement (clés composites séparées par des virgules)
+func Key(values ...interface{}) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = fmt.Sprint(value)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Record enregistre une entrée d'audit dans tx. Si fields est fourni, seules ces colonnes
+// sont conservées, et uniquement si leur valeur a changé (aucune entrée sinon).
+func Record(tx *gorm.DB, auditableType, auditableID, action string, before, after interface{}, fields ...string) error {
+	oldValues, err := snapshot(before)
+	if err != nil {
+		return err
+	}
+	newValues, err := snapshot(after)
+	if err != nil {
+		return err
+	}
+	if len(fields) > 0 {
+		oldValues, newValues = changes(oldValues, newValues, fields)
+		if len(newValues) == 0 {
+			return nil
+		}
+	}
+
+	entry := Entry{
+		AuditableType: auditableType,
+		AuditableID:   auditableID,
+		Action:        action,
+		OldValues:     oldValues,
+		NewValues:     newValues,
+	}
+	if user, ok := UserFrom(tx.Statement.Context); ok {
+		entry.UserID = &user
+	}
+	return tx.Create(&entry).Error
+}
+
+// snapshot retourne les valeurs d'un model telles qu'exposées en JSON
+func snapshot(model interface{}) (Values, error) {
+	if model == nil || (reflect.ValueOf(model).Kind() == reflect.Ptr && reflect.ValueOf(model).IsNil()) {
+		return nil, nil
+	}
+	data, err := json.Marshal(model)
+	if err != nil {
+		return nil, err
+	}
+	var values Values
+	err = json.Unmarshal(data, &values)
+	return values, err
+}
+
+// changes retourne les anciennes et nouvelles valeurs des colonnes modifiées
+func changes(before, after Values, fields []string) (Values, Values) {
+	oldValues, newValues := Values{}, Values{}
+	for _, field := range fields {
+		if !reflect.DeepEqual(before[field], after[field]) {
+			oldValues[field] = before[field]
+			newValues[field] = after[field]
+		}
+	}
+	return oldValues, newValues
+}
+`
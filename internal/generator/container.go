@@ -0,0 +1,98 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go-scaffold/internal/inflection"
+	"go-scaffold/internal/parser"
+)
+
+// GenerateContainer génère app/container.go, qui construit les repositories et les
+// contrôleurs de tous les schémas du projet à partir d'une connexion
+func (g *Generator) GenerateContainer() error {
+	filename := filepath.Join("app", "container.go")
+
+	// Créer le répertoire s'il n'existe pas
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+
+	content := g.generateContainerContent(g.containerSchemas())
+	return os.WriteFile(filename, []byte(content), 0644)
+}
+
+// containerSchemas retourne, triés par model, le schéma courant et les schémas du projet
+// dont le contrôleur a déjà été généré
+func (g *Generator) containerSchemas() []*parser.Schema {
+	schemas := []*parser.Schema{g.Schema}
+	for _, schema := range g.Schemas {
+		if schema.Model == g.Schema.Model {
+			continue
+		}
+		controller := filepath.Join("app", "controllers", inflection.Snake(schema.Model)+"_controller.go")
+		if _, err := os.Stat(controller); err == nil {
+			schemas = append(schemas, schema)
+		}
+	}
+	sort.Slice(schemas, func(i, j int) bool {
+		return schemas[i].Model < schemas[j].Model
+	})
+	return schemas
+}
+
+func (g *Generator) generateContainerContent(schemas []*parser.Schema) string {
+	var sb strings.Builder
+
+	sb.WriteString("package app\n\n")
+	writeImports(&sb, "app/controllers", "app/repositories", "app/requests",
+		"github.com/go-playground/validator/v10", "gorm.io/gorm")
+
+	// Champs alignés comme le ferait gofmt, bloc par bloc
+	var components [][2]string
+	for _, schema := range schemas {
+		components = append(components,
+			[2]string{schema.Model + "Repository", "repositories." + schema.Model + "Interface"},
+			[2]string{schema.Model + "Controller", "*controllers." + schema.Model + "Controller"},
+		)
+	}
+
+	sb.WriteString("// Container regroupe les dépendances de l'application: connexion, validateur,\n")
+	sb.WriteString("// repositories et contrôleurs générés à partir des schémas\n")
+	sb.WriteString("type Container struct {\n")
+	writeAlignedFields(&sb, [][2]string{{"DB", "*gorm.DB"}, {"Validate", "*validator.Validate"}})
+	sb.WriteString("\n")
+	writeAlignedFields(&sb, components)
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// NewContainer construit les repositories et les contrôleurs sur la connexion db\n")
+	sb.WriteString("func NewContainer(db *gorm.DB) *Container {\n")
+	sb.WriteString("\tc := &Container{\n")
+	sb.WriteString("\t\tDB:       db,\n")
+	sb.WriteString("\t\tValidate: requests.NewValidator(),\n")
+	sb.WriteString("\t}\n\n")
+	for _, schema := range schemas {
+		sb.WriteString(fmt.Sprintf("\tc.%sRepository = repositories.New%sRepository(db)\n", schema.Model, schema.Model))
+		sb.WriteString(fmt.Sprintf("\tc.%sController = controllers.New%sController(c.%sRepository, c.Validate)\n", schema.Model, schema.Model, schema.Model))
+	}
+	sb.WriteString("\n\treturn c\n")
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// writeAlignedFields écrit des champs de struct alignés sur le plus long nom
+func writeAlignedFields(sb *strings.Builder, fields [][2]string) {
+	width := 0
+	for _, field := range fields {
+		if len(field[0]) > width {
+			width = len(field[0])
+		}
+	}
+	for _, field := range fields {
+		sb.WriteString(fmt.Sprintf("\t%-*s %s\n", width, field[0], field[1]))
+	}
+}
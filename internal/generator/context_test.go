@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"testing"
+
+	"go-scaffold/internal/config"
+)
+
+// authorSchemas déclarent des auteurs dont les posts sont audités
+var authorSchemas = []string{`
+table: authors
+model: Author
+columns: [{name: id, type: bigint, primary: true, auto_increment: true}]
+relations: [{type: has_many, model: Post, foreign_key: author_id}]`, `
+table: posts
+model: Post
+audit: true
+columns:
+  - {name: id, type: bigint, primary: true, auto_increment: true}
+  - {name: author_id, type: bigint}`}
+
+func TestGenerateContext(t *testing.T) {
+	files := generate(t, nil, authorSchemas...)
+
+	contains(t, files, "app/repositories/author_repository.go",
+		"FindByID(ctx context.Context, id int64",
+		"r.db.WithContext(ctx)",
+	)
+	contains(t, files, "app/controllers/author_controller.go", "ctrl.repo.FindByID(c.Request.Context(), id")
+}
+
+func TestGenerateLegacyRepositories(t *testing.T) {
+	cfg := config.Default()
+	cfg.Repositories = config.RepositoriesLegacy
+	files := generate(t, cfg, authorSchemas...)
+
+	contains(t, files, "app/repositories/post_repository.go", "FindByID(id int64", "WithContext(ctx context.Context)")
+	excludes(t, files, "app/repositories/post_repository.go", "FindByID(ctx")
+	// Les posts préchargés depuis un auteur sont audités: le contexte doit les atteindre
+	contains(t, files, "app/controllers/author_controller.go", "ctrl.repo.WithContext(c.Request.Context()).")
+}
+
+func TestGenerateContainer(t *testing.T) {
+	files := generate(t, nil, authorSchemas...)
+
+	contains(t, files, "app/container.go",
+		"func NewContainer(db *gorm.DB) *Container {",
+		"AuthorRepository repositories.AuthorInterface",
+		"c.PostRepository = repositories.NewPostRepository(db)",
+	)
+}
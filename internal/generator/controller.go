@@ -5,12 +5,15 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"go-scaffold/internal/inflection"
+	"go-scaffold/internal/parser"
 )
 
 // GenerateController génère le fichier contrôleur
 func (g *Generator) GenerateController() error {
 	modelName := g.Schema.Model
-	filename := filepath.Join("app", "controllers", toSnakeCase(modelName)+"_controller.go")
+	filename := filepath.Join("app", "controllers", inflection.Snake(modelName)+"_controller.go")
 
 	// Créer le répertoire s'il n'existe pas
 	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
@@ -25,17 +28,29 @@ func (g *Generator) generateControllerContent() string {
 	var sb strings.Builder
 	modelName := g.Schema.Model
 	controllerName := modelName + "Controller"
-	varName := toCamelCase(modelName)
+	varName := inflection.Camel(modelName)
+	pluralName := g.pluralVar()
 
 	sb.WriteString("package controllers\n\n")
 	sb.WriteString("import (\n")
+	if g.Schema.Tree || g.Schema.Versioned || g.Schema.SoftDeletes || g.Schema.CursorPagination() {
+		sb.WriteString("\t\"errors\"\n")
+	}
 	sb.WriteString("\t\"net/http\"\n")
-	sb.WriteString("\t\"strconv\"\n\n")
+	sb.WriteString("\t\"strconv\"\n")
+	if g.Schema.Versioned {
+		sb.WriteString("\t\"strings\"\n")
+	}
+	sb.WriteString("\n")
+	sb.WriteString("\t\"app/fieldset\"\n")
+	sb.WriteString("\t\"app/include\"\n")
 	sb.WriteString("\t\"app/models\"\n")
+	sb.WriteString("\t\"app/query\"\n")
 	sb.WriteString("\t\"app/repositories\"\n")
 	sb.WriteString("\t\"app/requests\"\n\n")
 	sb.WriteString("\t\"github.com/gin-gonic/gin\"\n")
 	sb.WriteString("\t\"github.com/go-playground/validator/v10\"\n")
+	sb.WriteString("\t\"gorm.io/gorm\"\n")
 	sb.WriteString(")\n\n")
 
 	// Structure du contrôleur
@@ -46,52 +61,102 @@ func (g *Generator) generateControllerContent() string {
 	sb.WriteString("}\n\n")
 
 	// Constructeur
-	sb.WriteString(fmt.Sprintf("// New%s crée une nouvelle instance du contrôleur\n", controllerName))
-	sb.WriteString(fmt.Sprintf("func New%s() *%s {\n", controllerName, controllerName))
+	sb.WriteString(fmt.Sprintf("// New%s crée une nouvelle instance du contrôleur à partir de ses dépendances\n", controllerName))
+	sb.WriteString(fmt.Sprintf("func New%s(repo repositories.%sInterface, validate *validator.Validate) *%s {\n", controllerName, modelName, controllerName))
 	sb.WriteString(fmt.Sprintf("\treturn &%s{\n", controllerName))
-	sb.WriteString(fmt.Sprintf("\t\trepo: repositories.New%sRepository(),\n", modelName))
-	sb.WriteString("\t\tvalidate: validator.New(),\n")
+	sb.WriteString("\t\trepo:     repo,\n")
+	sb.WriteString("\t\tvalidate: validate,\n")
 	sb.WriteString("\t}\n")
 	sb.WriteString("}\n\n")
 
 	// Méthode Index (List)
-	sb.WriteString(fmt.Sprintf("// Index récupère la liste des %ss\n", varName))
-	sb.WriteString(fmt.Sprintf("// @Summary Liste des %ss\n", varName))
-	sb.WriteString("// @Description Récupère tous les " + varName + "s avec pagination\n")
+	sb.WriteString(fmt.Sprintf("// Index récupère la liste des %s\n", pluralName))
+	sb.WriteString(fmt.Sprintf("// @Summary Liste des %s\n", pluralName))
+	sb.WriteString("// @Description Récupère tous les " + pluralName + " avec pagination\n")
 	sb.WriteString("// @Tags " + modelName + "\n")
 	sb.WriteString("// @Accept json\n")
 	sb.WriteString("// @Produce json\n")
-	sb.WriteString("// @Param page query int false \"Numéro de page\" default(1)\n")
+	if g.Schema.CursorPagination() {
+		sb.WriteString("// @Param cursor query string false \"Curseur next_cursor ou prev_cursor d'une page précédente\"\n")
+	} else {
+		sb.WriteString("// @Param page query int false \"Numéro de page\" default(1)\n")
+	}
 	sb.WriteString("// @Param page_size query int false \"Taille de page\" default(10)\n")
+	if len(g.listScopes()) > 0 {
+		g.writeScopeDocParam(&sb)
+	}
+	g.writeQueryDocParams(&sb)
+	if g.search() {
+		g.writeSearchDocParam(&sb)
+	}
+	if g.includable() {
+		g.writeIncludeDocParam(&sb)
+	}
+	g.writeFieldsetDocParam(&sb)
 	sb.WriteString(fmt.Sprintf("// @Success 200 {object} map[string]interface{}\n"))
-	sb.WriteString("// @Router /" + toSnakeCase(modelName) + "s [get]\n")
+	sb.WriteString("// @Router /" + inflection.Snake(modelName) + "s [get]\n")
 	sb.WriteString(fmt.Sprintf("func (ctrl *%s) Index(c *gin.Context) {\n", controllerName))
+	nested := len(g.nestedRelations()) > 0
+	if nested {
+		// Index et les listes imbriquées partagent le même corps
+		sb.WriteString("\tctrl.list(c)\n")
+		sb.WriteString("}\n\n")
+		sb.WriteString("// list répond à Index et aux listes imbriquées, restreintes par les scopes\n")
+		sb.WriteString(fmt.Sprintf("func (ctrl *%s) list(c *gin.Context, scopes ...func(*gorm.DB) *gorm.DB) {\n", controllerName))
+	}
 	sb.WriteString("\t// Paramètres de pagination\n")
-	sb.WriteString("\tpage, _ := strconv.Atoi(c.DefaultQuery(\"page\", \"1\"))\n")
-	sb.WriteString("\tpageSize, _ := strconv.Atoi(c.DefaultQuery(\"page_size\", \"10\"))\n\n")
-	sb.WriteString("\tif page < 1 {\n")
-	sb.WriteString("\t\tpage = 1\n")
-	sb.WriteString("\t}\n")
+	if g.Schema.CursorPagination() {
+		sb.WriteString("\tcursor := c.Query(\"cursor\")\n")
+		sb.WriteString("\tpageSize, _ := strconv.Atoi(c.DefaultQuery(\"page_size\", \"10\"))\n\n")
+	} else {
+		sb.WriteString("\tpage, _ := strconv.Atoi(c.DefaultQuery(\"page\", \"1\"))\n")
+		sb.WriteString("\tpageSize, _ := strconv.Atoi(c.DefaultQuery(\"page_size\", \"10\"))\n\n")
+		sb.WriteString("\tif page < 1 {\n")
+		sb.WriteString("\t\tpage = 1\n")
+		sb.WriteString("\t}\n")
+	}
 	sb.WriteString("\tif pageSize < 1 || pageSize > 100 {\n")
 	sb.WriteString("\t\tpageSize = 10\n")
 	sb.WriteString("\t}\n\n")
-	sb.WriteString(fmt.Sprintf("\t%ss, total, err := ctrl.repo.FindAll(page, pageSize)\n", varName))
-	sb.WriteString("\tif err != nil {\n")
-	sb.WriteString("\t\tc.JSON(http.StatusInternalServerError, gin.H{\n")
-	sb.WriteString("\t\t\t\"error\": \"Erreur lors de la récupération des données\",\n")
-	sb.WriteString("\t\t})\n")
-	sb.WriteString("\t\treturn\n")
-	sb.WriteString("\t}\n\n")
-	sb.WriteString("\tc.JSON(http.StatusOK, gin.H{\n")
-	sb.WriteString(fmt.Sprintf("\t\t\"data\": %ss,\n", varName))
-	sb.WriteString("\t\t\"pagination\": gin.H{\n")
-	sb.WriteString("\t\t\t\"page\": page,\n")
-	sb.WriteString("\t\t\t\"page_size\": pageSize,\n")
-	sb.WriteString("\t\t\t\"total\": total,\n")
-	sb.WriteString("\t\t\t\"total_pages\": (total + int64(pageSize) - 1) / int64(pageSize),\n")
-	sb.WriteString("\t\t},\n")
-	sb.WriteString("\t})\n")
-	sb.WriteString("}\n\n")
+	if len(g.listScopes()) > 0 {
+		g.writeScopeSelection(&sb, !nested)
+	} else if !nested {
+		sb.WriteString("\tvar scopes []func(*gorm.DB) *gorm.DB\n\n")
+	}
+	g.writeQuerySelection(&sb)
+	if g.search() && g.Schema.CursorPagination() {
+		g.writeSearchSelection(&sb)
+	}
+	g.writeIncludeSelection(&sb)
+	sb.WriteString("\tscopes = append(scopes, preload)\n\n")
+	g.writeFieldsetSelection(&sb)
+	args := g.listArgs() + ", scopes..."
+	if g.Schema.CursorPagination() {
+		g.writeCursorIndex(&sb, args)
+		sb.WriteString("}\n\n")
+	} else {
+		if g.search() {
+			g.writeSearchIndex(&sb, args)
+		} else {
+			sb.WriteString(fmt.Sprintf("\t%s, total, err := %s\n", pluralName, g.repoCall("FindAll", args)))
+		}
+		sb.WriteString("\tif err != nil {\n")
+		sb.WriteString("\t\tc.JSON(http.StatusInternalServerError, gin.H{\n")
+		sb.WriteString("\t\t\t\"error\": \"Erreur lors de la récupération des données\",\n")
+		sb.WriteString("\t\t})\n")
+		sb.WriteString("\t\treturn\n")
+		sb.WriteString("\t}\n\n")
+		sb.WriteString("\tc.JSON(http.StatusOK, gin.H{\n")
+		sb.WriteString(fmt.Sprintf("\t\t\"data\": selection.Filter(%s),\n", pluralName))
+		sb.WriteString("\t\t\"pagination\": gin.H{\n")
+		sb.WriteString("\t\t\t\"page\": page,\n")
+		sb.WriteString("\t\t\t\"page_size\": pageSize,\n")
+		sb.WriteString("\t\t\t\"total\": total,\n")
+		sb.WriteString("\t\t\t\"total_pages\": (total + int64(pageSize) - 1) / int64(pageSize),\n")
+		sb.WriteString("\t\t},\n")
+		sb.WriteString("\t})\n")
+		sb.WriteString("}\n\n")
+	}
 
 	// Méthode Show (Get by ID)
 	sb.WriteString(fmt.Sprintf("// Show récupère un %s par ID\n", varName))
@@ -100,18 +165,25 @@ func (g *Generator) generateControllerContent() string {
 	sb.WriteString("// @Tags " + modelName + "\n")
 	sb.WriteString("// @Accept json\n")
 	sb.WriteString("// @Produce json\n")
-	sb.WriteString("// @Param id path string true \"ID du " + varName + "\"\n")
+	g.writeKeyDocParams(&sb)
+	if g.includable() {
+		g.writeIncludeDocParam(&sb)
+	}
 	sb.WriteString(fmt.Sprintf("// @Success 200 {object} models.%s\n", modelName))
-	sb.WriteString("// @Router /" + toSnakeCase(modelName) + "s/{id} [get]\n")
+	sb.WriteString("// @Router /" + g.resourceName() + g.keyDocRoute() + " [get]\n")
 	sb.WriteString(fmt.Sprintf("func (ctrl *%s) Show(c *gin.Context) {\n", controllerName))
-	sb.WriteString("\tid := c.Param(\"id\")\n\n")
-	sb.WriteString(fmt.Sprintf("\t%s, err := ctrl.repo.FindByID(id)\n", varName))
+	g.writeKeyParsing(&sb)
+	g.writeIncludeSelection(&sb)
+	sb.WriteString(fmt.Sprintf("\t%s, err := %s\n", varName, g.repoCall("FindByID", g.keyArgs()+", preload")))
 	sb.WriteString("\tif err != nil {\n")
 	sb.WriteString("\t\tc.JSON(http.StatusNotFound, gin.H{\n")
 	sb.WriteString("\t\t\t\"error\": \"Enregistrement non trouvé\",\n")
 	sb.WriteString("\t\t})\n")
 	sb.WriteString("\t\treturn\n")
 	sb.WriteString("\t}\n\n")
+	if g.Schema.Versioned {
+		g.writeETag(&sb, varName)
+	}
 	sb.WriteString(fmt.Sprintf("\tc.JSON(http.StatusOK, %s)\n", varName))
 	sb.WriteString("}\n\n")
 
@@ -125,7 +197,7 @@ func (g *Generator) generateControllerContent() string {
 	sb.WriteString(fmt.Sprintf("// @Param %s body requests.Create%sRequest true \"Données du %s\"\n", 
 		varName, modelName, varName))
 	sb.WriteString(fmt.Sprintf("// @Success 201 {object} models.%s\n", modelName))
-	sb.WriteString("// @Router /" + toSnakeCase(modelName) + "s [post]\n")
+	sb.WriteString("// @Router /" + inflection.Snake(modelName) + "s [post]\n")
 	sb.WriteString(fmt.Sprintf("func (ctrl *%s) Store(c *gin.Context) {\n", controllerName))
 	sb.WriteString(fmt.Sprintf("\tvar req requests.Create%sRequest\n\n", modelName))
 	sb.WriteString("\tif err := c.ShouldBindJSON(&req); err != nil {\n")
@@ -143,37 +215,142 @@ func (g *Generator) generateControllerContent() string {
 	sb.WriteString("\t\treturn\n")
 	sb.WriteString("\t}\n\n")
 	sb.WriteString(fmt.Sprintf("\t%s := req.ToModel()\n\n", varName))
-	sb.WriteString(fmt.Sprintf("\tif err := ctrl.repo.Create(&%s); err != nil {\n", varName))
+	sb.WriteString(fmt.Sprintf("\tif err := %s; err != nil {\n", g.repoCall("Create", "&"+varName)))
+	if g.Schema.Tree {
+		g.writeTreeErrors(&sb, false)
+	}
 	sb.WriteString("\t\tc.JSON(http.StatusInternalServerError, gin.H{\n")
 	sb.WriteString("\t\t\t\"error\": \"Erreur lors de la création\",\n")
 	sb.WriteString("\t\t})\n")
 	sb.WriteString("\t\treturn\n")
 	sb.WriteString("\t}\n\n")
+	if g.Schema.Versioned {
+		g.writeETag(&sb, varName)
+	}
 	sb.WriteString(fmt.Sprintf("\tc.JSON(http.StatusCreated, %s)\n", varName))
 	sb.WriteString("}\n\n")
 
-	// Méthode Update
-	sb.WriteString(fmt.Sprintf("// Update met à jour un %s\n", varName))
-	sb.WriteString(fmt.Sprintf("// @Summary Mettre à jour un %s\n", varName))
-	sb.WriteString("// @Description Met à jour un " + varName + " existant\n")
+	// Routes imbriquées sous les parents belongs_to
+	g.writeNestedController(&sb)
+
+	// Méthode Update (PUT): remplacement complet, toutes les colonnes sont requises
+	g.writeUpdateHandler(&sb, updateHandler{
+		name:        "Update",
+		method:      "put",
+		summary:     "Remplacer un " + varName,
+		description: "Remplace toutes les données d'un " + varName + " existant",
+		request:     "Create" + modelName + "Request",
+		apply:       "req.Fill(" + varName + ")",
+	})
+
+	// Méthode Patch (PATCH): mise à jour partielle des seuls champs fournis
+	g.writeUpdateHandler(&sb, updateHandler{
+		name:        "Patch",
+		method:      "patch",
+		summary:     "Mettre à jour partiellement un " + varName,
+		description: "Met à jour les seuls champs fournis d'un " + varName + " existant",
+		request:     "Update" + modelName + "Request",
+		apply:       "req.UpdateModel(" + varName + ")",
+	})
+
+	// Méthode Delete
+	sb.WriteString(fmt.Sprintf("// Delete supprime un %s\n", varName))
+	sb.WriteString(fmt.Sprintf("// @Summary Supprimer un %s\n", varName))
+	sb.WriteString("// @Description Supprime un " + varName + " par son ID\n")
 	sb.WriteString("// @Tags " + modelName + "\n")
 	sb.WriteString("// @Accept json\n")
 	sb.WriteString("// @Produce json\n")
-	sb.WriteString("// @Param id path string true \"ID du " + varName + "\"\n")
-	sb.WriteString(fmt.Sprintf("// @Param %s body requests.Update%sRequest true \"Nouvelles données\"\n", 
-		varName, modelName))
+	g.writeKeyDocParams(&sb)
+	if g.Schema.Versioned {
+		sb.WriteString("// @Param If-Match header string false \"Version attendue (ETag)\"\n")
+	}
+	sb.WriteString("// @Success 204\n")
+	if g.Schema.Versioned {
+		sb.WriteString("// @Failure 409 {object} map[string]interface{}\n")
+	}
+	sb.WriteString("// @Router /" + g.resourceName() + g.keyDocRoute() + " [delete]\n")
+	sb.WriteString(fmt.Sprintf("func (ctrl *%s) Delete(c *gin.Context) {\n", controllerName))
+	g.writeKeyParsing(&sb)
+	switch {
+	case g.Schema.Versioned:
+		g.writeIfMatchDelete(&sb)
+	case g.Schema.TenantScoped:
+		g.writeTenantExistence(&sb)
+	}
+	deleteArgs := g.keyArgs()
+	if g.Schema.Versioned {
+		deleteArgs += ", expected..."
+	}
+	sb.WriteString(fmt.Sprintf("\tif err := %s; err != nil {\n", g.repoCall("Delete", deleteArgs)))
+	if g.Schema.Versioned {
+		g.writeVersionConflict(&sb)
+	}
+	sb.WriteString("\t\tc.JSON(http.StatusInternalServerError, gin.H{\n")
+	sb.WriteString("\t\t\t\"error\": \"Erreur lors de la suppression\",\n")
+	sb.WriteString("\t\t})\n")
+	sb.WriteString("\t\treturn\n")
+	sb.WriteString("\t}\n\n")
+	sb.WriteString("\tc.Status(http.StatusNoContent)\n")
+	sb.WriteString("}\n\n")
+
+	if g.Schema.Tree {
+		g.writeTreeHandlers(&sb)
+	}
+
+	if g.Schema.SoftDeletes {
+		g.writeSoftDeleteHandlers(&sb)
+	}
+
+	if g.Schema.Audit {
+		g.writeAuditHandler(&sb)
+	}
+
+	g.writePrimaryKeyParser(&sb)
+
+	return sb.String()
+}
+
+// updateHandler décrit un handler de mise à jour (PUT ou PATCH)
+type updateHandler struct {
+	name        string
+	method      string
+	summary     string
+	description string
+	request     string
+	apply       string
+}
+
+func (g *Generator) writeUpdateHandler(sb *strings.Builder, h updateHandler) {
+	modelName := g.Schema.Model
+	controllerName := modelName + "Controller"
+	varName := inflection.Camel(modelName)
+
+	sb.WriteString(fmt.Sprintf("// %s %s\n", h.name, strings.ToLower(h.description[:1])+h.description[1:]))
+	sb.WriteString(fmt.Sprintf("// @Summary %s\n", h.summary))
+	sb.WriteString("// @Description " + h.description + "\n")
+	sb.WriteString("// @Tags " + modelName + "\n")
+	sb.WriteString("// @Accept json\n")
+	sb.WriteString("// @Produce json\n")
+	g.writeKeyDocParams(sb)
+	sb.WriteString(fmt.Sprintf("// @Param %s body requests.%s true \"Nouvelles données\"\n", varName, h.request))
+	if g.Schema.Versioned {
+		sb.WriteString("// @Param If-Match header string false \"Version attendue (ETag)\"\n")
+	}
 	sb.WriteString(fmt.Sprintf("// @Success 200 {object} models.%s\n", modelName))
-	sb.WriteString("// @Router /" + toSnakeCase(modelName) + "s/{id} [put]\n")
-	sb.WriteString(fmt.Sprintf("func (ctrl *%s) Update(c *gin.Context) {\n", controllerName))
-	sb.WriteString("\tid := c.Param(\"id\")\n\n")
-	sb.WriteString(fmt.Sprintf("\t%s, err := ctrl.repo.FindByID(id)\n", varName))
+	if g.Schema.Versioned {
+		sb.WriteString("// @Failure 409 {object} map[string]interface{}\n")
+	}
+	sb.WriteString("// @Router /" + g.resourceName() + g.keyDocRoute() + " [" + h.method + "]\n")
+	sb.WriteString(fmt.Sprintf("func (ctrl *%s) %s(c *gin.Context) {\n", controllerName, h.name))
+	g.writeKeyParsing(sb)
+	sb.WriteString(fmt.Sprintf("\t%s, err := %s\n", varName, g.repoCall("FindByID", g.keyArgs())))
 	sb.WriteString("\tif err != nil {\n")
 	sb.WriteString("\t\tc.JSON(http.StatusNotFound, gin.H{\n")
 	sb.WriteString("\t\t\t\"error\": \"Enregistrement non trouvé\",\n")
 	sb.WriteString("\t\t})\n")
 	sb.WriteString("\t\treturn\n")
 	sb.WriteString("\t}\n\n")
-	sb.WriteString(fmt.Sprintf("\tvar req requests.Update%sRequest\n\n", modelName))
+	sb.WriteString(fmt.Sprintf("\tvar req requests.%s\n\n", h.request))
 	sb.WriteString("\tif err := c.ShouldBindJSON(&req); err != nil {\n")
 	sb.WriteString("\t\tc.JSON(http.StatusBadRequest, gin.H{\n")
 	sb.WriteString("\t\t\t\"error\": \"Données invalides\",\n")
@@ -188,36 +365,103 @@ func (g *Generator) generateControllerContent() string {
 	sb.WriteString("\t\t})\n")
 	sb.WriteString("\t\treturn\n")
 	sb.WriteString("\t}\n\n")
-	sb.WriteString(fmt.Sprintf("\treq.UpdateModel(%s)\n\n", varName))
-	sb.WriteString(fmt.Sprintf("\tif err := ctrl.repo.Update(%s); err != nil {\n", varName))
+	if g.Schema.Versioned {
+		g.writeIfMatch(sb)
+	}
+	sb.WriteString(fmt.Sprintf("\t%s\n\n", h.apply))
+	sb.WriteString(fmt.Sprintf("\tif err := %s; err != nil {\n", g.repoCall("Update", varName+", req.Fields()...")))
+	if g.Schema.Tree {
+		g.writeTreeErrors(sb, true)
+	}
+	if g.Schema.Versioned {
+		g.writeVersionConflict(sb)
+	}
 	sb.WriteString("\t\tc.JSON(http.StatusInternalServerError, gin.H{\n")
 	sb.WriteString("\t\t\t\"error\": \"Erreur lors de la mise à jour\",\n")
 	sb.WriteString("\t\t})\n")
 	sb.WriteString("\t\treturn\n")
 	sb.WriteString("\t}\n\n")
+	if g.Schema.Versioned {
+		g.writeETag(sb, varName)
+	}
 	sb.WriteString(fmt.Sprintf("\tc.JSON(http.StatusOK, %s)\n", varName))
 	sb.WriteString("}\n\n")
+}
 
-	// Méthode Delete
-	sb.WriteString(fmt.Sprintf("// Delete supprime un %s\n", varName))
-	sb.WriteString(fmt.Sprintf("// @Summary Supprimer un %s\n", varName))
-	sb.WriteString("// @Description Supprime un " + varName + " par son ID\n")
-	sb.WriteString("// @Tags " + modelName + "\n")
-	sb.WriteString("// @Accept json\n")
-	sb.WriteString("// @Produce json\n")
-	sb.WriteString("// @Param id path string true \"ID du " + varName + "\"\n")
-	sb.WriteString("// @Success 204\n")
-	sb.WriteString("// @Router /" + toSnakeCase(modelName) + "s/{id} [delete]\n")
-	sb.WriteString(fmt.Sprintf("func (ctrl *%s) Delete(c *gin.Context) {\n", controllerName))
-	sb.WriteString("\tid := c.Param(\"id\")\n\n")
-	sb.WriteString("\tif err := ctrl.repo.Delete(id); err != nil {\n")
-	sb.WriteString("\t\tc.JSON(http.StatusInternalServerError, gin.H{\n")
-	sb.WriteString("\t\t\t\"error\": \"Erreur lors de la suppression\",\n")
-	sb.WriteString("\t\t})\n")
+// writeKeyDocParams écrit les paramètres Swagger de la clé primaire
+func (g *Generator) writeKeyDocParams(sb *strings.Builder) {
+	for _, col := range g.Schema.PrimaryKeys() {
+		sb.WriteString(fmt.Sprintf("// @Param %s path %s true \"%s du %s\"\n",
+			col.Name, keyDocType(col), col.Name, inflection.Camel(g.Schema.Model)))
+	}
+}
+
+// keyDocType retourne le type Swagger d'un paramètre de route
+func keyDocType(col parser.Column) string {
+	if col.IsZeroable() {
+		return "integer"
+	}
+	return "string"
+}
+
+// writeKeyParsing écrit la lecture de la clé primaire depuis l'URL
+func (g *Generator) writeKeyParsing(sb *strings.Builder) {
+	sb.WriteString(fmt.Sprintf("\t%s, ok := ctrl.primaryKey(c)\n", g.keyArgs()))
+	sb.WriteString("\tif !ok {\n")
 	sb.WriteString("\t\treturn\n")
 	sb.WriteString("\t}\n\n")
-	sb.WriteString("\tc.Status(http.StatusNoContent)\n")
+}
+
+// writePrimaryKeyParser écrit la méthode primaryKey du contrôleur, qui lit et
+// convertit chaque colonne de la clé primaire depuis les paramètres de la route
+func (g *Generator) writePrimaryKeyParser(sb *strings.Builder) {
+	keys := g.Schema.PrimaryKeys()
+	controllerName := g.Schema.Model + "Controller"
+
+	var types, zeros []string
+	for _, col := range keys {
+		types = append(types, col.BaseGoType())
+		if col.IsZeroable() {
+			zeros = append(zeros, "0")
+		} else {
+			zeros = append(zeros, "\"\"")
+		}
+	}
+	failure := strings.Join(append(zeros, "false"), ", ")
+
+	sb.WriteString("// primaryKey lit la clé primaire depuis les paramètres de la route\n")
+	sb.WriteString(fmt.Sprintf("func (ctrl *%s) primaryKey(c *gin.Context) (%s) {\n",
+		controllerName, strings.Join(append(types, "bool"), ", ")))
+	for _, col := range keys {
+		writeParamParsing(sb, inflection.Camel(col.Name), col.Name, col.BaseGoType(), "return "+failure)
+	}
+	sb.WriteString(fmt.Sprintf("\treturn %s, true\n", g.keyArgs()))
 	sb.WriteString("}\n")
+}
 
-	return sb.String()
+// writeParamParsing écrit la lecture du paramètre de route param dans la variable varName
+// de type goType; failure est l'instruction exécutée après la réponse 400
+func writeParamParsing(sb *strings.Builder, varName, param, goType, failure string) {
+	switch goType {
+	case "int":
+		sb.WriteString(fmt.Sprintf("\t%s, err := strconv.Atoi(c.Param(\"%s\"))\n", varName, param))
+	case "int64":
+		sb.WriteString(fmt.Sprintf("\t%s, err := strconv.ParseInt(c.Param(\"%s\"), 10, 64)\n", varName, param))
+	case "int16", "int32":
+		bits := strings.TrimPrefix(goType, "int")
+		sb.WriteString(fmt.Sprintf("\t%sValue, err := strconv.ParseInt(c.Param(\"%s\"), 10, %s)\n", varName, param, bits))
+	default:
+		sb.WriteString(fmt.Sprintf("\t%s := c.Param(\"%s\")\n", varName, param))
+		return
+	}
+	sb.WriteString("\tif err != nil {\n")
+	sb.WriteString("\t\tc.JSON(http.StatusBadRequest, gin.H{\n")
+	sb.WriteString(fmt.Sprintf("\t\t\t\"error\": \"Paramètre %s invalide\",\n", param))
+	sb.WriteString("\t\t})\n")
+	sb.WriteString(fmt.Sprintf("\t\t%s\n", failure))
+	sb.WriteString("\t}\n")
+	// ParseInt renvoie un int64, converti une fois la valeur validée
+	if goType == "int16" || goType == "int32" {
+		sb.WriteString(fmt.Sprintf("\t%s := %s(%sValue)\n", varName, goType, varName))
+	}
 }
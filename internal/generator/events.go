@@ -0,0 +1,177 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go-scaffold/internal/inflection"
+)
+
+// Les schémas events: true émettent des événements typés (ArticleCreated, ArticleUpdated,
+// ArticleDeleted) depuis les hooks GORM After*. Ils sont distribués dans le processus par
+// le package app/events, auprès des observers enregistrés avec events.Listen.
+
+// eventName retourne le nom d'un événement du model (ex: article.created)
+func (g *Generator) eventName(action string) string {
+	return inflection.Snake(g.Schema.Model) + "." + action
+}
+
+// GenerateEvents génère le package app/events (dispatcher d'événements)
+func (g *Generator) GenerateEvents() error {
+	filename := filepath.Join("app", "events", "dispatcher.go")
+
+	// Créer le répertoire s'il n'existe pas
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, []byte(eventsDispatcherContent), 0644)
+}
+
+// writeModelEvents écrit les types d'événements du model et les hooks qui les émettent
+func (g *Generator) writeModelEvents(sb *strings.Builder) {
+	modelName := g.Schema.Model
+	varName := inflection.Camel(modelName)
+
+	for _, e := range []struct{ action, description string }{
+		{"Created", "après la création d'un"},
+		{"Updated", "après la mise à jour d'un"},
+		{"Deleted", "après la suppression d'un"},
+	} {
+		eventType := modelName + e.action
+		sb.WriteString(fmt.Sprintf("// %s est émis %s %s\n", eventType, e.description, varName))
+		sb.WriteString(fmt.Sprintf("type %s struct {\n", eventType))
+		sb.WriteString(fmt.Sprintf("\t%s *%s\n", modelName, modelName))
+		if e.action == "Updated" {
+			sb.WriteString("\tChanged []string // Colonnes écrites par la mise à jour\n")
+		}
+		sb.WriteString("}\n\n")
+		sb.WriteString("// EventName retourne le nom de l'événement\n")
+		sb.WriteString(fmt.Sprintf("func (%s) EventName() string {\n", eventType))
+		sb.WriteString(fmt.Sprintf("\treturn %q\n", g.eventName(strings.ToLower(e.action))))
+		sb.WriteString("}\n\n")
+	}
+
+	// Hooks: une erreur d'un observer annule la transaction GORM
+	sb.WriteString(fmt.Sprintf("// AfterCreate hook GORM: émet %sCreated\n", modelName))
+	sb.WriteString(fmt.Sprintf("func (m *%s) AfterCreate(tx *gorm.DB) error {\n", modelName))
+	sb.WriteString(fmt.Sprintf("\treturn events.Dispatch(tx.Statement.Context, %sCreated{%s: m})\n", modelName, modelName))
+	sb.WriteString("}\n\n")
+	sb.WriteString(fmt.Sprintf("// AfterUpdate hook GORM: émet %sUpdated avec les colonnes écrites\n", modelName))
+	sb.WriteString(fmt.Sprintf("func (m *%s) AfterUpdate(tx *gorm.DB) error {\n", modelName))
+	sb.WriteString(fmt.Sprintf("\treturn events.Dispatch(tx.Statement.Context, %sUpdated{%s: m, Changed: tx.Statement.Selects})\n", modelName, modelName))
+	sb.WriteString("}\n\n")
+	sb.WriteString(fmt.Sprintf("// AfterDelete hook GORM: émet %sDeleted\n", modelName))
+	sb.WriteString(fmt.Sprintf("func (m *%s) AfterDelete(tx *gorm.DB) error {\n", modelName))
+	sb.WriteString(fmt.Sprintf("\treturn events.Dispatch(tx.Statement.Context, %sDeleted{%s: m})\n", modelName, modelName))
+	sb.WriteString("}\n\n")
+}
+
+// writeEventsDelete écrit une suppression qui charge d'abord l'enregistrement, afin que
+// l'événement Deleted porte le model supprimé (db est la connexion du repository, éventuellement
+// Unscoped; missing est renvoyé si l'enregistrement n'existe pas et versioned conditionne
+// la suppression par la version attendue de Delete)
+func (g *Generator) writeEventsDelete(sb *strings.Builder, db, missing string, versioned bool) {
+	varName := inflection.Camel(g.Schema.Model)
+	sb.WriteString(fmt.Sprintf("\tvar %s models.%s\n", varName, g.Schema.Model))
+	sb.WriteString(fmt.Sprintf("\tif err := %s.Where(\"%s\", %s).First(&%s).Error; err != nil {\n", db, g.keyWhere(), g.keyArgs(), varName))
+	sb.WriteString("\t\tif errors.Is(err, gorm.ErrRecordNotFound) {\n")
+	sb.WriteString(fmt.Sprintf("\t\t\treturn %s\n", missing))
+	sb.WriteString("\t\t}\n")
+	sb.WriteString("\t\treturn err\n")
+	sb.WriteString("\t}\n")
+	if versioned {
+		g.writeVersionedDelete(sb, db, "&"+varName, "\t")
+		sb.WriteString("\treturn nil\n")
+		return
+	}
+	sb.WriteString(fmt.Sprintf("\treturn %s.Delete(&%s).Error\n", db, varName))
+}
+
+// writeEventsRestore écrit une restauration qui charge l'enregistrement supprimé, afin
+// que l'événement Updated porte le model restauré et la colonne deleted_at
+func (g *Generator) writeEventsRestore(sb *strings.Builder) {
+	varName := inflection.Camel(g.Schema.Model)
+	sb.WriteString(fmt.Sprintf("\tvar %s models.%s\n", varName, g.Schema.Model))
+	sb.WriteString(fmt.Sprintf("\tif err := %s.Unscoped().Where(\"%s AND deleted_at IS NOT NULL\", %s).First(&%s).Error; err != nil {\n", g.repoDB(), g.keyWhere(), g.keyArgs(), varName))
+	sb.WriteString("\t\tif errors.Is(err, gorm.ErrRecordNotFound) {\n")
+	sb.WriteString(fmt.Sprintf("\t\t\treturn %s\n", g.softDeleteMissingError()))
+	sb.WriteString("\t\t}\n")
+	sb.WriteString("\t\treturn err\n")
+	sb.WriteString("\t}\n")
+	sb.WriteString(fmt.Sprintf("\treturn %s.Unscoped().Model(&%s).Select(\"deleted_at\").Update(\"deleted_at\", nil).Error\n", g.repoDB(), varName))
+}
+
+// eventsDispatcherContent contient le dispatcher d'événements des projets générés
+const eventsDispatcherContent = `package events
+
+import (
+	"context"
+	"sync"
+)
+
+// Event est implémenté par les événements émis par les models
+type Event interface {
+	EventName() string
+}
+
+// Handler traite un événement. Une erreur interrompt la distribution et, pour les
+// événements émis par les hooks GORM, annule l'opération en cours.
+type Handler func(ctx context.Context, event Event) error
+
+// Dispatcher distribue les événements aux handlers enregistrés, dans le processus
+// et dans l'ordre d'enregistrement
+type Dispatcher struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+// NewDispatcher crée un dispatcher sans handler
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{handlers: map[string][]Handler{}}
+}
+
+// Listen enregistre un handler pour les événements du nom indiqué
+func (d *Dispatcher) Listen(name string, handler Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers[name] = append(d.handlers[name], handler)
+}
+
+// Dispatch distribue un événement à ses handlers
+func (d *Dispatcher) Dispatch(ctx context.Context, event Event) error {
+	d.mu.RLock()
+	handlers := d.handlers[event.EventName()]
+	d.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// defaultDispatcher est le dispatcher utilisé par les models générés
+var defaultDispatcher = NewDispatcher()
+
+// Listen enregistre un handler typé sur le dispatcher par défaut:
+//
+//	events.Listen(func(ctx context.Context, e models.ArticleCreated) error { ... })
+func Listen[E Event](handler func(ctx context.Context, event E) error) {
+	var zero E
+	defaultDispatcher.Listen(zero.EventName(), func(ctx context.Context, event Event) error {
+		return handler(ctx, event.(E))
+	})
+}
+
+// Dispatch distribue un événement sur le dispatcher par défaut
+func Dispatch(ctx context.Context, event Event) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return defaultDispatcher.Dispatch(ctx, event)
+}
+`
@@ -0,0 +1,218 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// La liste accepte ?fields=id,title,published_at: le SELECT est restreint à ces colonnes
+// et la réponse ne sérialise qu'elles. La clé primaire (et la colonne du curseur) est
+// toujours renvoyée, les clés étrangères sont lues pour charger les relations demandées
+// par ?include=, et les colonnes hidden ne sont jamais sélectionnables.
+
+// fieldsetVar retourne le nom de la description des colonnes sélectionnables du model
+func (g *Generator) fieldsetVar() string {
+	return g.Schema.Model + "Fields"
+}
+
+// GenerateFieldset génère app/fieldset/fieldset.go, commun à toutes les listes
+func (g *Generator) GenerateFieldset() error {
+	filename := filepath.Join("app", "fieldset", "fieldset.go")
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, []byte(fieldsetPackageContent), 0644)
+}
+
+// fieldsetKeys retourne les colonnes toujours renvoyées: clé primaire et colonne du curseur
+func (g *Generator) fieldsetKeys() []string {
+	var keys []string
+	for _, key := range g.Schema.PrimaryKeys() {
+		keys = append(keys, key.Name)
+	}
+	if g.Schema.CursorPagination() && !g.Schema.IsPrimaryKey(g.Schema.CursorColumn().Name) {
+		keys = append(keys, g.Schema.CursorColumn().Name)
+	}
+	return keys
+}
+
+// fieldsetForeign retourne les colonnes locales dont les relations ont besoin pour être chargées
+func (g *Generator) fieldsetForeign() []string {
+	var columns []string
+	seen := map[string]bool{}
+	for _, rel := range g.includeRelations() {
+		column := rel.References
+		if rel.Type == "belongs_to" {
+			column = rel.ForeignKey
+		}
+		if column == "" || seen[column] || g.Schema.IsPrimaryKey(column) || !g.Schema.HasColumn(column) {
+			continue
+		}
+		seen[column] = true
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+// writeModelFieldset écrit la description des colonnes sélectionnables par ?fields=
+func (g *Generator) writeModelFieldset(sb *strings.Builder) {
+	quoted := func(names []string) string {
+		var values []string
+		for _, name := range names {
+			values = append(values, fmt.Sprintf("%q", name))
+		}
+		return "[]string{" + strings.Join(values, ", ") + "},"
+	}
+	var columns []string
+	for _, col := range g.Schema.VisibleColumns() {
+		columns = append(columns, col.Name)
+	}
+
+	sb.WriteString(fmt.Sprintf("// %s décrit les colonnes des %s sélectionnables par ?fields= (sans les\n", g.fieldsetVar(), g.pluralVar()))
+	sb.WriteString("// colonnes hidden)\n")
+	sb.WriteString(fmt.Sprintf("var %s = fieldset.Set{\n", g.fieldsetVar()))
+	fields := [][2]string{{"Keys:", quoted(g.fieldsetKeys())}}
+	if foreign := g.fieldsetForeign(); len(foreign) > 0 {
+		fields = append(fields, [2]string{"Foreign:", quoted(foreign)})
+	}
+	fields = append(fields, [2]string{"Columns:", quoted(columns)})
+	writeAlignedFields(sb, fields)
+	sb.WriteString("}\n\n")
+}
+
+// writeFieldsetDocParam documente le paramètre fields de la liste
+func (g *Generator) writeFieldsetDocParam(sb *strings.Builder) {
+	sb.WriteString("// @Param fields query string false \"Colonnes renvoyées, séparées par des virgules (clé primaire toujours incluse)\"\n")
+}
+
+// writeFieldsetSelection écrit, dans Index, la traduction de ?fields= en scope de sélection
+func (g *Generator) writeFieldsetSelection(sb *strings.Builder) {
+	sb.WriteString("\t// Colonnes renvoyées (?fields=id,title)\n")
+	sb.WriteString(fmt.Sprintf("\tselection, err := fieldset.Parse(c.Request.URL.Query(), models.%s)\n", g.fieldsetVar()))
+	g.writeQueryError(sb)
+	sb.WriteString("\tscopes = append(scopes, selection.Scope())\n\n")
+}
+
+// fieldsetPackageContent contient le package de sélection des colonnes des projets générés
+const fieldsetPackageContent = `package fieldset
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Set décrit les colonnes d'un model pour ?fields=
+type Set struct {
+	Keys    []string // Toujours lues et renvoyées (clé primaire, colonne du curseur)
+	Foreign []string // Lues pour charger les relations, renvoyées si demandées
+	Columns []string // Colonnes sélectionnables (les colonnes hidden en sont exclues)
+}
+
+// Error signale une colonne inconnue ou non sélectionnable dans ?fields=
+type Error struct {
+	Name string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("Champ inconnu: %s", e.Name)
+}
+
+// Selection contient les colonnes demandées par ?fields=; une sélection nil les renvoie toutes
+type Selection struct {
+	set     Set
+	columns []string
+}
+
+// Parse valide ?fields=title,published_at contre set. Sans paramètre fields, la sélection
+// retournée est nil.
+func Parse(values url.Values, set Set) (*Selection, error) {
+	params, ok := values["fields"]
+	if !ok {
+		return nil, nil
+	}
+	selection := &Selection{set: set, columns: append([]string(nil), set.Keys...)}
+	for _, param := range params {
+		for _, name := range strings.Split(param, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" || contains(selection.columns, name) {
+				continue
+			}
+			if !contains(set.Columns, name) {
+				return nil, &Error{Name: name}
+			}
+			selection.columns = append(selection.columns, name)
+		}
+	}
+	return selection, nil
+}
+
+// Scope retourne le scope qui restreint le SELECT aux colonnes demandées et aux clés
+// étrangères des relations; il est ignoré pendant le comptage du total
+func (s *Selection) Scope() func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if s == nil {
+			return db
+		}
+		if _, counting := db.Statement.Dest.(*int64); counting {
+			return db
+		}
+		columns := append([]string(nil), s.columns...)
+		for _, column := range s.set.Foreign {
+			if !contains(columns, column) {
+				columns = append(columns, column)
+			}
+		}
+		return db.Select(columns)
+	}
+}
+
+// Filter retourne la sérialisation de value (objet ou liste) réduite aux colonnes
+// demandées; les relations chargées sont conservées. Si value ne peut pas être
+// sérialisée, elle est retournée telle quelle.
+func (s *Selection) Filter(value interface{}) interface{} {
+	if s == nil {
+		return value
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var list []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &list); err == nil {
+		for _, object := range list {
+			s.strip(object)
+		}
+		return list
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return value
+	}
+	s.strip(object)
+	return object
+}
+
+// strip retire d'un objet sérialisé les colonnes non demandées
+func (s *Selection) strip(object map[string]json.RawMessage) {
+	for _, column := range s.set.Columns {
+		if !contains(s.columns, column) {
+			delete(object, column)
+		}
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+`
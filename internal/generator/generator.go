@@ -4,27 +4,36 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	"go-scaffold/internal/config"
+	"go-scaffold/internal/inflection"
 	"go-scaffold/internal/parser"
 )
 
 // Generator gère la génération de code
 type Generator struct {
-	Schema *parser.Schema
+	Schema  *parser.Schema
+	Config  *config.Config
+	Schemas []*parser.Schema // Schémas du projet, pour résoudre les relations
 }
 
 // NewGenerator crée une nouvelle instance de Generator
-func NewGenerator(schema *parser.Schema) *Generator {
+func NewGenerator(schema *parser.Schema, cfg *config.Config) *Generator {
+	if cfg == nil {
+		cfg = config.Default()
+	}
 	return &Generator{
 		Schema: schema,
+		Config: cfg,
 	}
 }
 
 // GenerateModel génère le fichier model
 func (g *Generator) GenerateModel() error {
 	modelName := g.Schema.Model
-	filename := filepath.Join("app", "models", toSnakeCase(modelName)+".go")
+	filename := filepath.Join("app", "models", inflection.Snake(modelName)+".go")
 
 	// Créer le répertoire s'il n'existe pas
 	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
@@ -32,29 +41,57 @@ func (g *Generator) GenerateModel() error {
 	}
 
 	content := g.generateModelContent()
-	return os.WriteFile(filename, []byte(content), 0644)
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		return err
+	}
+
+	if g.Schema.Events {
+		if err := g.GenerateEvents(); err != nil {
+			return err
+		}
+	}
+	if g.Schema.Audit {
+		if err := g.GenerateAudit(); err != nil {
+			return err
+		}
+	}
+	if g.Schema.TenantScoped {
+		if err := g.GenerateTenant(); err != nil {
+			return err
+		}
+	}
+	if err := g.GenerateQuery(); err != nil {
+		return err
+	}
+	if err := g.GenerateInclude(); err != nil {
+		return err
+	}
+	if err := g.GenerateFieldset(); err != nil {
+		return err
+	}
+	if parser.NullableStyle() == parser.NullableGeneric {
+		return g.GenerateTypes()
+	}
+	return nil
 }
 
 func (g *Generator) generateModelContent() string {
 	var sb strings.Builder
 
 	sb.WriteString("package models\n\n")
-	sb.WriteString("import (\n")
-	
-	needsTime := false
-	for _, col := range g.Schema.Columns {
-		if strings.Contains(col.GetGoType(), "time.Time") {
-			needsTime = true
-			break
-		}
+	imports := append(columnImports(g.Schema.Columns), "gorm.io/gorm", "app/fieldset")
+	if imp := g.keyStrategyImport(); imp != "" {
+		imports = append(imports, imp)
 	}
-	
-	if needsTime {
-		sb.WriteString("\t\"time\"\n")
+	imports = append(imports, g.scopeImports()...)
+	if g.Schema.Events {
+		imports = append(imports, "app/events")
 	}
-	
-	sb.WriteString("\t\"gorm.io/gorm\"\n")
-	sb.WriteString(")\n\n")
+	imports = append(imports, "app/query")
+	if g.search() {
+		imports = append(imports, g.searchImports()...)
+	}
+	writeImports(&sb, imports...)
 
 	// Structure principale
 	sb.WriteString(fmt.Sprintf("// %s représente la table %s\n", g.Schema.Model, g.Schema.Table))
@@ -62,13 +99,17 @@ func (g *Generator) generateModelContent() string {
 
 	// Générer les champs
 	for _, col := range g.Schema.Columns {
-		fieldName := toPascalCase(col.Name)
+		fieldName := inflection.Pascal(col.Name)
 		goType := col.GetGoType()
 		jsonTag := col.GetJSONTag()
+		if col.Hidden {
+			// Les colonnes hidden ne sont jamais renvoyées
+			jsonTag = "json:\"-\""
+		}
 		
 		// Construire les tags GORM
 		gormTags := []string{}
-		if col.Primary {
+		if g.Schema.IsPrimaryKey(col.Name) {
 			gormTags = append(gormTags, "primaryKey")
 		}
 		if col.AutoIncrement {
@@ -83,7 +124,25 @@ func (g *Generator) generateModelContent() string {
 		if col.Size > 0 && col.Type == "string" {
 			gormTags = append(gormTags, fmt.Sprintf("size:%d", col.Size))
 		}
-		if col.Default != nil {
+		if col.SoftDelete {
+			gormTags = append(gormTags, "index")
+		}
+		// Horodatage géré par GORM, indépendamment du nom du champ
+		if col.BaseGoType() == "time.Time" {
+			switch col.Name {
+			case "created_at":
+				gormTags = append(gormTags, "autoCreateTime")
+			case "updated_at":
+				gormTags = append(gormTags, "autoUpdateTime")
+			}
+		}
+		if _, custom := parser.LookupType(col.Type); custom || col.IsDecimal() {
+			gormTags = append(gormTags, fmt.Sprintf("type:%s", col.GetDBType(g.Config.Dialect)))
+		}
+		// Pas de défaut GORM sur les booléens et nombres non nullables: GORM ignorerait
+		// leur valeur zéro (false, 0) à la création. La request applique le défaut.
+		// La version (jamais 0) reçoit son défaut de GORM à la création
+		if col.Default != nil && (col.Nullable || !col.IsZeroable() || col.Version) {
 			gormTags = append(gormTags, fmt.Sprintf("default:%v", col.Default))
 		}
 		if col.Name != "" {
@@ -111,39 +170,38 @@ func (g *Generator) generateModelContent() string {
 
 	// Ajouter les relations
 	for _, rel := range g.Schema.Relations {
+		// Un parent morph_to est identifié par ses colonnes <nom>_type et <nom>_id
+		if rel.Type == "morph_to" {
+			continue
+		}
 		sb.WriteString(fmt.Sprintf("\t// Relation: %s\n", rel.Type))
 		relModel := rel.Model
+		name := g.relationName(rel)
+		
+		relTag := fmt.Sprintf("foreignKey:%s", rel.ForeignKey)
+		if constraint := rel.Constraint(); constraint != "" {
+			relTag += ";constraint:" + constraint
+		}
 		
+		var goType, gormTag string
 		switch rel.Type {
-		case "belongs_to":
-			sb.WriteString(fmt.Sprintf("\t%s *%s `json:\"%s,omitempty\" gorm:\"foreignKey:%s\"`\n",
-				relModel,
-				relModel,
-				toSnakeCase(relModel),
-				rel.ForeignKey,
-			))
+		case "belongs_to", "has_one":
+			goType, gormTag = "*"+relModel, relTag
 		case "has_many":
-			sb.WriteString(fmt.Sprintf("\t%ss []%s `json:\"%ss,omitempty\" gorm:\"foreignKey:%s\"`\n",
-				relModel,
-				relModel,
-				toSnakeCase(relModel),
-				rel.ForeignKey,
-			))
-		case "has_one":
-			sb.WriteString(fmt.Sprintf("\t%s *%s `json:\"%s,omitempty\" gorm:\"foreignKey:%s\"`\n",
-				relModel,
-				relModel,
-				toSnakeCase(relModel),
-				rel.ForeignKey,
-			))
+			goType, gormTag = "[]"+relModel, relTag
 		case "many_to_many":
-			sb.WriteString(fmt.Sprintf("\t%ss []%s `json:\"%ss,omitempty\" gorm:\"many2many:%s\"`\n",
-				relModel,
-				relModel,
-				toSnakeCase(relModel),
-				rel.PivotTable,
-			))
+			goType, gormTag = "[]"+relModel, "many2many:"+rel.PivotTable
+		case "morph_many":
+			goType, gormTag = "[]"+relModel, g.morphTag(rel)
+		case "morph_one":
+			goType, gormTag = "*"+relModel, g.morphTag(rel)
 		}
+		sb.WriteString(fmt.Sprintf("\t%s %s `json:\"%s,omitempty\" gorm:\"%s\"`\n",
+			inflection.Pascal(name),
+			goType,
+			name,
+			gormTag,
+		))
 	}
 
 	sb.WriteString("}\n\n")
@@ -154,12 +212,37 @@ func (g *Generator) generateModelContent() string {
 	sb.WriteString(fmt.Sprintf("\treturn \"%s\"\n", g.Schema.Table))
 	sb.WriteString("}\n\n")
 
+	// Scopes réutilisables
+	g.writeModelScopes(&sb)
+
+	// Colonnes filtrables et triables de la liste
+	g.writeModelQuery(&sb)
+
+	// Recherche plein texte
+	if g.search() {
+		g.writeModelSearch(&sb)
+	}
+
+	// Relations chargées par défaut
+	g.writeModelIncludes(&sb)
+
+	// Colonnes sélectionnables de la liste
+	g.writeModelFieldset(&sb)
+
+	if g.Schema.TenantScoped {
+		g.writeTenantMarker(&sb)
+	}
+
 	// Hooks GORM (optionnel)
-	sb.WriteString(fmt.Sprintf("// BeforeCreate hook GORM\n"))
-	sb.WriteString(fmt.Sprintf("func (m *%s) BeforeCreate(tx *gorm.DB) error {\n", g.Schema.Model))
-	sb.WriteString("\t// Logique avant création\n")
-	sb.WriteString("\treturn nil\n")
-	sb.WriteString("}\n\n")
+	if g.keyStrategyImport() != "" {
+		g.writeKeyGeneration(&sb)
+	} else {
+		sb.WriteString(fmt.Sprintf("// BeforeCreate hook GORM\n"))
+		sb.WriteString(fmt.Sprintf("func (m *%s) BeforeCreate(tx *gorm.DB) error {\n", g.Schema.Model))
+		sb.WriteString("\t// Logique avant création\n")
+		sb.WriteString("\treturn nil\n")
+		sb.WriteString("}\n\n")
+	}
 
 	sb.WriteString(fmt.Sprintf("// BeforeUpdate hook GORM\n"))
 	sb.WriteString(fmt.Sprintf("func (m *%s) BeforeUpdate(tx *gorm.DB) error {\n", g.Schema.Model))
@@ -167,37 +250,221 @@ func (g *Generator) generateModelContent() string {
 	sb.WriteString("\treturn nil\n")
 	sb.WriteString("}\n")
 
+	if g.Schema.Events {
+		sb.WriteString("\n")
+		g.writeModelEvents(&sb)
+	}
+
 	return sb.String()
 }
 
-// Fonctions utilitaires
-func toSnakeCase(s string) string {
-	var result strings.Builder
-	for i, r := range s {
-		if i > 0 && r >= 'A' && r <= 'Z' {
-			result.WriteRune('_')
-		}
-		result.WriteRune(r)
+// relationName retourne le nom (snake_case) d'une relation, qui donne le champ Go,
+// la clé JSON et le nom de préchargement: son name s'il est défini (author, parent),
+// sinon le nom du model cible. Pour morph_many/morph_one, name désigne la relation
+// polymorphe et le nom est dérivé du model.
+func (g *Generator) relationName(rel parser.Relation) string {
+	if rel.Name != "" && !rel.IsMorph() {
+		return rel.Name
+	}
+	switch rel.Type {
+	case "has_many", "many_to_many", "morph_many":
+		return inflection.Pluralize(inflection.Snake(rel.Model))
+	}
+	return inflection.Snake(rel.Model)
+}
+
+// relationField retourne le nom du champ Go d'une relation ("" pour morph_to, sans champ)
+func (g *Generator) relationField(rel parser.Relation) string {
+	if rel.Type == "morph_to" {
+		return ""
 	}
-	return strings.ToLower(result.String())
+	return inflection.Pascal(g.relationName(rel))
+}
+
+// morphTag retourne le tag GORM d'une relation morph_many/morph_one.
+// Le discriminant enregistré est le nom du model parent.
+func (g *Generator) morphTag(rel parser.Relation) string {
+	name := inflection.Pascal(rel.Name)
+	return fmt.Sprintf("polymorphic:%s;polymorphicType:%s;polymorphicId:%s;polymorphicValue:%s",
+		name,
+		inflection.Pascal(rel.MorphTypeColumn()),
+		inflection.Pascal(rel.MorphIDColumn()),
+		g.Schema.Model,
+	)
+}
+
+// resourceName retourne le nom de la ressource dans les routes (ex: categories)
+func (g *Generator) resourceName() string {
+	return inflection.Pluralize(inflection.Snake(g.Schema.Model))
 }
 
-func toPascalCase(s string) string {
-	words := strings.FieldsFunc(s, func(r rune) bool {
-		return r == '_' || r == '-' || r == ' '
-	})
-	for i, word := range words {
-		if len(word) > 0 {
-			words[i] = strings.ToUpper(word[:1]) + strings.ToLower(word[1:])
+// pluralVar retourne le nom de variable d'une liste de models (ex: categories)
+func (g *Generator) pluralVar() string {
+	return inflection.Camel(inflection.Pluralize(g.Schema.Model))
+}
+
+// findSchema retourne le schéma du projet correspondant à un model
+func (g *Generator) findSchema(model string) *parser.Schema {
+	if model == g.Schema.Model {
+		return g.Schema
+	}
+	for _, schema := range g.Schemas {
+		if schema.Model == model {
+			return schema
 		}
 	}
-	return strings.Join(words, "")
+	return nil
+}
+
+// tableFor retourne la table d'un model, déduite de son nom si son schéma est inconnu
+func (g *Generator) tableFor(model string) string {
+	if schema := g.findSchema(model); schema != nil {
+		return schema.Table
+	}
+	return inflection.Pluralize(inflection.Snake(model))
+}
+
+// keyParams retourne la déclaration des paramètres de la clé primaire (ex: "orderId string, lineNo int")
+func (g *Generator) keyParams() string {
+	var params []string
+	for _, col := range g.Schema.PrimaryKeys() {
+		params = append(params, inflection.Camel(col.Name)+" "+col.BaseGoType())
+	}
+	return strings.Join(params, ", ")
+}
+
+// keyArgs retourne les arguments de la clé primaire (ex: "orderId, lineNo")
+func (g *Generator) keyArgs() string {
+	var args []string
+	for _, col := range g.Schema.PrimaryKeys() {
+		args = append(args, inflection.Camel(col.Name))
+	}
+	return strings.Join(args, ", ")
 }
 
-func toCamelCase(s string) string {
-	pascal := toPascalCase(s)
-	if len(pascal) == 0 {
-		return pascal
+// keyWhere retourne la condition SQL sur la clé primaire (ex: "order_id = ? AND line_no = ?")
+func (g *Generator) keyWhere() string {
+	var conds []string
+	for _, col := range g.Schema.PrimaryKeys() {
+		conds = append(conds, col.Name+" = ?")
 	}
-	return strings.ToLower(pascal[:1]) + pascal[1:]
+	return strings.Join(conds, " AND ")
+}
+
+// keyRoute retourne le chemin des paramètres de la clé primaire (ex: "/:order_id/:line_no")
+func (g *Generator) keyRoute() string {
+	var route string
+	for _, col := range g.Schema.PrimaryKeys() {
+		route += "/:" + col.Name
+	}
+	return route
+}
+
+// keyDocRoute retourne le chemin de la clé primaire au format Swagger (ex: "/{order_id}/{line_no}")
+func (g *Generator) keyDocRoute() string {
+	var route string
+	for _, col := range g.Schema.PrimaryKeys() {
+		route += "/{" + col.Name + "}"
+	}
+	return route
+}
+
+// isGeneratedKey indique si une colonne de clé primaire est générée à l'insertion
+// (auto-incrément, ou UUID d'une clé simple) et n'est donc pas fournie par le client
+func (g *Generator) isGeneratedKey(col parser.Column) bool {
+	if !g.Schema.IsPrimaryKey(col.Name) {
+		return false
+	}
+	return g.Schema.KeyStrategy() != parser.IDNone
+}
+
+// keyStrategyImport retourne l'import nécessaire à la génération de la clé primaire
+func (g *Generator) keyStrategyImport() string {
+	switch g.Schema.KeyStrategy() {
+	case parser.IDUUIDv4, parser.IDUUIDv7:
+		return "github.com/google/uuid"
+	case parser.IDULID:
+		return "github.com/oklog/ulid/v2"
+	}
+	return ""
+}
+
+// writeKeyGeneration écrit le hook BeforeCreate générant la clé primaire non renseignée
+func (g *Generator) writeKeyGeneration(sb *strings.Builder) {
+	strategy := g.Schema.KeyStrategy()
+	field := "m." + inflection.Pascal(g.Schema.PrimaryKeys()[0].Name)
+	labels := map[string]string{parser.IDUUIDv4: "UUID v4", parser.IDUUIDv7: "UUID v7", parser.IDULID: "ULID"}
+
+	sb.WriteString(fmt.Sprintf("// BeforeCreate hook GORM: génère la clé primaire (%s) si elle n'est pas renseignée\n", labels[strategy]))
+	sb.WriteString(fmt.Sprintf("func (m *%s) BeforeCreate(tx *gorm.DB) error {\n", g.Schema.Model))
+	sb.WriteString(fmt.Sprintf("\tif %s == \"\" {\n", field))
+	switch strategy {
+	case parser.IDUUIDv4:
+		sb.WriteString(fmt.Sprintf("\t\t%s = uuid.NewString()\n", field))
+	case parser.IDUUIDv7:
+		sb.WriteString("\t\tid, err := uuid.NewV7()\n")
+		sb.WriteString("\t\tif err != nil {\n")
+		sb.WriteString("\t\t\treturn err\n")
+		sb.WriteString("\t\t}\n")
+		sb.WriteString(fmt.Sprintf("\t\t%s = id.String()\n", field))
+	case parser.IDULID:
+		sb.WriteString(fmt.Sprintf("\t\t%s = ulid.Make().String()\n", field))
+	}
+	sb.WriteString("\t}\n")
+	sb.WriteString("\treturn nil\n")
+	sb.WriteString("}\n\n")
+}
+
+// columnImports retourne les imports nécessaires aux types Go des colonnes
+func columnImports(columns []parser.Column) []string {
+	var imports []string
+	for _, col := range columns {
+		imports = append(imports, col.GetImports()...)
+	}
+	return imports
+}
+
+// writeImports écrit un bloc d'import dédoublonné en trois groupes:
+// bibliothèque standard, packages du projet puis dépendances externes
+func writeImports(sb *strings.Builder, imports ...string) {
+	seen := map[string]bool{}
+	var std, local, others []string
+	for _, path := range imports {
+		if path == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+		switch {
+		case isLocalImport(path):
+			local = append(local, path)
+		case strings.Contains(strings.Split(path, "/")[0], "."):
+			others = append(others, path)
+		default:
+			std = append(std, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(local)
+	sort.Strings(others)
+
+	sb.WriteString("import (\n")
+	first := true
+	for _, group := range [][]string{std, local, others} {
+		if len(group) == 0 {
+			continue
+		}
+		if !first {
+			sb.WriteString("\n")
+		}
+		first = false
+		for _, path := range group {
+			sb.WriteString(fmt.Sprintf("\t\"%s\"\n", path))
+		}
+	}
+	sb.WriteString(")\n\n")
+}
+
+// isLocalImport indique si un import désigne un package du projet généré
+func isLocalImport(path string) bool {
+	return path == "app" || path == "config" || strings.HasPrefix(path, "app/")
 }
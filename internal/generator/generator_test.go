@@ -0,0 +1,176 @@
+package generator
+
+import (
+	"fmt"
+	goparser "go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-scaffold/internal/config"
+	"go-scaffold/internal/parser"
+)
+
+// generate génère, dans un répertoire temporaire, le code des schémas YAML donnés avec
+// la configuration cfg, vérifie que chaque fichier Go produit est syntaxiquement valide
+// et retourne le contenu des fichiers par chemin relatif
+func generate(t *testing.T, cfg *config.Config, sources ...string) map[string]string {
+	t.Helper()
+	if cfg == nil {
+		cfg = config.Default()
+	}
+	cfg.Apply()
+	t.Cleanup(func() {
+		parser.SetDialect(parser.DialectPostgres)
+		parser.SetNullableStyle(parser.NullablePointer)
+		parser.SetTenantColumn("tenant_id")
+	})
+
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	var schemas []*parser.Schema
+	for i, src := range sources {
+		file := filepath.Join(dir, fmt.Sprintf("schema%d.yaml", i))
+		if err := os.WriteFile(file, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+		schema, err := parser.ParseSchema(file)
+		if err != nil {
+			t.Fatalf("schéma %d: %v", i, err)
+		}
+		schemas = append(schemas, schema)
+	}
+	for _, schema := range schemas {
+		parser.ResolveMorphColumns(schema, schemas)
+	}
+	if errs := parser.ValidateSchemas(schemas); len(errs) > 0 {
+		t.Fatalf("ValidateSchemas: %v", errs)
+	}
+
+	for _, schema := range schemas {
+		g := NewGenerator(schema, cfg)
+		g.Schemas = schemas
+		steps := []func() error{
+			g.GenerateModel,
+			g.GenerateRepository,
+			g.GenerateController,
+			g.GenerateRequests,
+			g.GenerateRoutes,
+			g.GenerateContainer,
+			g.GenerateMigration,
+		}
+		for _, step := range steps {
+			if err := step(); err != nil {
+				t.Fatalf("%s: %v", schema.Model, err)
+			}
+		}
+	}
+
+	files := map[string]string{}
+	fset := token.NewFileSet()
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || strings.HasSuffix(path, ".yaml") {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		files[filepath.ToSlash(rel)] = string(data)
+		if strings.HasSuffix(path, ".go") {
+			if _, err := goparser.ParseFile(fset, rel, data, goparser.AllErrors); err != nil {
+				t.Errorf("code généré invalide: %v", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+// contains vérifie que le fichier généré file contient chacun des fragments wants
+func contains(t *testing.T, files map[string]string, file string, wants ...string) {
+	t.Helper()
+	content, ok := files[file]
+	if !ok {
+		t.Errorf("fichier %s non généré", file)
+		return
+	}
+	for _, want := range wants {
+		if !strings.Contains(content, want) {
+			t.Errorf("%s ne contient pas %q", file, want)
+		}
+	}
+}
+
+// excludes vérifie que le fichier généré file ne contient aucun des fragments
+func excludes(t *testing.T, files map[string]string, file string, fragments ...string) {
+	t.Helper()
+	for _, fragment := range fragments {
+		if strings.Contains(files[file], fragment) {
+			t.Errorf("%s contient %q", file, fragment)
+		}
+	}
+}
+
+func TestGenerateModel(t *testing.T) {
+	cfg := config.Default()
+	cfg.Types = map[string]parser.TypeDefinition{
+		"sku": {GoType: "string", DBTypes: map[string]string{"default": "varchar(32)"}, Validation: "alphanum"},
+	}
+	files := generate(t, cfg, `
+table: products
+model: Product
+columns:
+  - {name: id, type: bigint, primary: true, auto_increment: true}
+  - {name: name, type: string, size: 120}
+  - {name: contact, type: email}
+  - {name: code, type: sku}
+  - {name: created_at, type: timestamp}`)
+
+	contains(t, files, "app/models/product.go",
+		"type Product struct {",
+		"Contact string `json:\"contact\" gorm:\"not null;type:varchar(255);column:contact\" validate:\"required,email\"`",
+		"Code string `json:\"code\" gorm:\"not null;type:varchar(32);column:code\" validate:\"required,alphanum\"`",
+		"return \"products\"",
+	)
+	contains(t, files, "app/requests/product_request.go", "type CreateProductRequest struct", "alphanum")
+	contains(t, files, "routes/product_routes.go", "/products")
+}
+
+func TestGenerateDecimal(t *testing.T) {
+	files := generate(t, nil, `
+table: invoices
+model: Invoice
+columns:
+  - {name: id, type: bigint, primary: true, auto_increment: true}
+  - {name: total, type: money}
+  - {name: tax_rate, type: decimal, precision: 5, scale: 2}`)
+
+	contains(t, files, "app/models/invoice.go",
+		"\"github.com/shopspring/decimal\"",
+		"Total decimal.Decimal `json:\"total\" gorm:\"not null;type:numeric(19,4);column:total\"",
+		"TotalCurrency string",
+		"TaxRate decimal.Decimal",
+	)
+	contains(t, files, "app/requests/invoice_request.go", "decimal=5:2", "iso4217")
+	contains(t, files, "database/migrations/create_invoices_table.sql",
+		"CREATE TABLE",
+		"numeric(19,4)",
+		"numeric(5,2)",
+		"char(3)",
+	)
+}
@@ -0,0 +1,221 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go-scaffold/internal/parser"
+)
+
+// Les relations ne sont plus préchargées systématiquement: la liste et l'affichage chargent
+// celles demandées par ?include=author,comments.author, ou à défaut celles déclarées
+// preload: true. Les chemins sont validés à l'exécution contre les relations GORM des
+// models, ce qui couvre les relations imbriquées sans référencer les autres schémas.
+
+// includeRelations retourne les relations chargeables du schéma (morph_to n'a pas de champ)
+func (g *Generator) includeRelations() []parser.Relation {
+	var relations []parser.Relation
+	for _, rel := range g.Schema.Relations {
+		if g.relationField(rel) != "" {
+			relations = append(relations, rel)
+		}
+	}
+	return relations
+}
+
+// includable indique si le schéma a des relations chargeables par ?include= (le paramètre
+// est validé dans tous les cas)
+func (g *Generator) includable() bool {
+	return len(g.includeRelations()) > 0
+}
+
+// includeReaches indique si ?include= peut atteindre, directement ou par des relations
+// imbriquées, un model dont le schéma vérifie match
+func (g *Generator) includeReaches(match func(*parser.Schema) bool) bool {
+	seen := map[string]bool{g.Schema.Model: true}
+	queue := []*parser.Schema{g.Schema}
+	for len(queue) > 0 {
+		schema := queue[0]
+		queue = queue[1:]
+		for _, rel := range schema.Relations {
+			if rel.Type == "morph_to" || seen[rel.Model] {
+				continue
+			}
+			seen[rel.Model] = true
+			related := g.findSchema(rel.Model)
+			if related == nil {
+				continue
+			}
+			if match(related) {
+				return true
+			}
+			queue = append(queue, related)
+		}
+	}
+	return false
+}
+
+// tenantIncludes indique si ?include= peut atteindre un model tenant_scoped depuis un schéma
+// qui ne l'est pas: ses callbacks exigent alors un tenant que le groupe de routes n'impose pas
+func (g *Generator) tenantIncludes() bool {
+	return !g.Schema.TenantScoped && g.includeReaches(func(schema *parser.Schema) bool {
+		return schema.TenantScoped
+	})
+}
+
+// includeMiddleware retourne le middleware à placer devant Index et Show quand leurs
+// relations chargeables atteignent un model tenant_scoped
+func (g *Generator) includeMiddleware() string {
+	if g.tenantIncludes() {
+		return "tenant.Middleware(), "
+	}
+	return ""
+}
+
+// includeDefaults retourne le nom de la liste des relations chargées par défaut
+func (g *Generator) includeDefaults() string {
+	return g.Schema.Model + "Includes"
+}
+
+// GenerateInclude génère app/include/include.go, commun à toutes les listes et à l'affichage
+func (g *Generator) GenerateInclude() error {
+	filename := filepath.Join("app", "include", "include.go")
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, []byte(includePackageContent), 0644)
+}
+
+// writeModelIncludes écrit la liste des relations chargées par défaut
+func (g *Generator) writeModelIncludes(sb *strings.Builder) {
+	var defaults []string
+	for _, rel := range g.includeRelations() {
+		if rel.Preload {
+			defaults = append(defaults, fmt.Sprintf("%q", g.relationName(rel)))
+		}
+	}
+	sb.WriteString(fmt.Sprintf("// %s liste les relations des %s chargées quand ?include= est absent\n", g.includeDefaults(), g.pluralVar()))
+	sb.WriteString(fmt.Sprintf("var %s = []string{%s}\n\n", g.includeDefaults(), strings.Join(defaults, ", ")))
+}
+
+// writeIncludeDocParam documente le paramètre include de la liste et de l'affichage
+func (g *Generator) writeIncludeDocParam(sb *strings.Builder) {
+	var names []string
+	for _, rel := range g.includeRelations() {
+		names = append(names, g.relationName(rel))
+	}
+	sb.WriteString(fmt.Sprintf("// @Param include query string false \"Relations à charger, séparées par des virgules (%s)\"\n", strings.Join(names, ", ")))
+}
+
+// writeIncludeSelection écrit, dans Index et Show, la traduction de ?include= en scope de préchargement
+func (g *Generator) writeIncludeSelection(sb *strings.Builder) {
+	var names []string
+	for _, rel := range g.includeRelations() {
+		names = append(names, g.relationName(rel))
+	}
+	if len(names) > 0 {
+		sb.WriteString(fmt.Sprintf("\t// Relations à charger (?include=%s)\n", strings.Join(names, ",")))
+	} else {
+		sb.WriteString("\t// Aucune relation chargeable: ?include= n'accepte qu'une liste vide\n")
+	}
+	sb.WriteString(fmt.Sprintf("\tpreload, err := include.Parse(c.Request.URL.Query(), models.%s{}, models.%s)\n", g.Schema.Model, g.includeDefaults()))
+	g.writeQueryError(sb)
+}
+
+// includePackageContent contient le package de chargement des relations des projets générés
+const includePackageContent = `package include
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Error signale une relation inconnue dans ?include=
+type Error struct {
+	Path string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("Relation inconnue: %s", e.Path)
+}
+
+// schemas met en cache l'analyse des models par GORM
+var schemas sync.Map
+
+// Parse valide les relations demandées par ?include= (séparées par des virgules, imbriquées
+// avec un point: comments.author) contre les relations de model, et retourne le scope qui
+// les précharge. Sans paramètre include, les relations defaults sont chargées; un
+// paramètre vide n'en charge aucune.
+func Parse(values url.Values, model interface{}, defaults []string) (func(*gorm.DB) *gorm.DB, error) {
+	names := defaults
+	if params, ok := values["include"]; ok {
+		names = nil
+		for _, param := range params {
+			for _, name := range strings.Split(param, ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+
+	root, err := schema.Parse(model, &schemas, schema.NamingStrategy{})
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(names))
+	for _, name := range names {
+		path, err := resolve(root, name)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return Preload(paths...), nil
+}
+
+// Preload retourne le scope qui précharge les chemins GORM (Comments.Author); il est
+// ignoré pendant le comptage du total
+func Preload(paths ...string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if _, counting := db.Statement.Dest.(*int64); counting {
+			return db
+		}
+		for _, path := range paths {
+			db = db.Preload(path)
+		}
+		return db
+	}
+}
+
+// resolve traduit un chemin de clés JSON (comments.author) en chemin de champs GORM
+func resolve(s *schema.Schema, name string) (string, error) {
+	var fields []string
+	for _, key := range strings.Split(name, ".") {
+		rel := relation(s, key)
+		if rel == nil {
+			return "", &Error{Path: name}
+		}
+		fields = append(fields, rel.Name)
+		s = rel.FieldSchema
+	}
+	return strings.Join(fields, "."), nil
+}
+
+// relation retourne la relation de s sérialisée sous la clé JSON key
+func relation(s *schema.Schema, key string) *schema.Relationship {
+	for _, rel := range s.Relationships.Relations {
+		if tag := strings.Split(rel.Field.Tag.Get("json"), ",")[0]; tag == key {
+			return rel
+		}
+	}
+	return nil
+}
+`
@@ -0,0 +1,91 @@
+package generator
+
+import (
+	"fmt"
+	"testing"
+)
+
+// postSchema retourne un schéma d'articles auquel s'ajoutent les options données
+func postSchema(options string) string {
+	return fmt.Sprintf(`
+table: posts
+model: Post
+%s
+columns:
+  - {name: id, type: bigint, primary: true, auto_increment: true}
+  - {name: title, type: string, size: 200}`, options)
+}
+
+func TestGenerateSoftDeletes(t *testing.T) {
+	files := generate(t, nil, postSchema("soft_deletes: true"))
+
+	contains(t, files, "app/models/post.go", "DeletedAt gorm.DeletedAt")
+	contains(t, files, "app/repositories/post_repository.go",
+		") FindTrashed(",
+		") Restore(",
+		") ForceDelete(",
+		"Unscoped()",
+		"ErrPostNotFound",
+	)
+	contains(t, files, "routes/post_routes.go", `"/trashed"`, `"/:id/restore"`, `"/:id/force"`)
+	contains(t, files, "database/migrations/create_posts_table.sql", "deleted_at")
+}
+
+func TestGenerateKeyStrategy(t *testing.T) {
+	files := generate(t, nil, `
+table: tokens
+model: Token
+id_strategy: uuid
+columns:
+  - {name: id, type: uuid, primary: true}
+  - {name: created_at, type: timestamp}`)
+
+	contains(t, files, "app/models/token.go",
+		"func (m *Token) BeforeCreate(tx *gorm.DB) error {",
+		"uuid.New",
+		"autoCreateTime",
+	)
+	excludes(t, files, "app/requests/token_request.go", "CreatedAt", "ID string")
+}
+
+func TestGenerateEvents(t *testing.T) {
+	files := generate(t, nil, postSchema("events: true"))
+
+	contains(t, files, "app/models/post.go",
+		"type PostCreated struct",
+		"type PostUpdated struct",
+		"type PostDeleted struct",
+		"func (m *Post) AfterCreate(tx *gorm.DB) error {",
+		"events.Dispatch(tx.Statement.Context, PostDeleted{Post: m})",
+	)
+	contains(t, files, "app/events/dispatcher.go", "package events", "func Dispatch(")
+}
+
+func TestGenerateAudit(t *testing.T) {
+	files := generate(t, nil, postSchema("audit: true\nsoft_deletes: true"))
+
+	contains(t, files, "app/repositories/post_repository.go",
+		"audit.Record(tx, \"Post\"",
+		"audit.Created",
+		"audit.Updated",
+		"audit.Deleted",
+		") History(",
+		"([]audit.Entry, error)",
+	)
+	contains(t, files, "routes/post_routes.go", `"/:id/history"`)
+	contains(t, files, "app/audit/audit.go", "package audit", "type Entry struct")
+}
+
+func TestGenerateVersioned(t *testing.T) {
+	files := generate(t, nil, postSchema("versioned: true"))
+
+	contains(t, files, "app/models/post.go", "Version int64", "default:1")
+	contains(t, files, "app/repositories/post_repository.go",
+		"ErrPostConflict",
+		"version ...int64",
+		`Where("version = ?", version[0])`,
+		"result.RowsAffected == 0",
+	)
+	contains(t, files, "app/controllers/post_controller.go", "If-Match", "ETag", "http.StatusConflict")
+	excludes(t, files, "app/requests/post_request.go", "Version")
+}
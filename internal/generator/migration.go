@@ -0,0 +1,269 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go-scaffold/internal/parser"
+)
+
+// GenerateMigration génère le script SQL de création de la table
+func (g *Generator) GenerateMigration() error {
+	filename := filepath.Join("database", "migrations", "create_"+g.Schema.Table+"_table.sql")
+
+	// Créer le répertoire s'il n'existe pas
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+
+	content := g.generateMigrationContent()
+	return os.WriteFile(filename, []byte(content), 0644)
+}
+
+func (g *Generator) generateMigrationContent() string {
+	var sb strings.Builder
+	dialect := g.Config.Dialect
+	table := g.Schema.Table
+
+	sb.WriteString(fmt.Sprintf("-- Table %s (dialecte %s)\n", table, dialect))
+	sb.WriteString("-- Fichier généré par go-scaffold, ne pas modifier manuellement\n\n")
+	sb.WriteString(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n", quoteIdent(dialect, table)))
+
+	var lines []string
+	var primaryKeys []string
+	inlinePrimary := false
+	for _, col := range g.Schema.Columns {
+		lines = append(lines, "    "+g.columnDefinition(col))
+		if col.Primary {
+			primaryKeys = append(primaryKeys, quoteIdent(dialect, col.Name))
+			if dialect == parser.DialectSQLite && col.AutoIncrement {
+				inlinePrimary = true
+			}
+		}
+	}
+	if len(primaryKeys) > 0 && !inlinePrimary {
+		lines = append(lines, fmt.Sprintf("    PRIMARY KEY (%s)", strings.Join(primaryKeys, ", ")))
+	}
+	for _, fk := range g.foreignKeys() {
+		lines = append(lines, "    "+g.foreignKeyDefinition(fk))
+	}
+	for _, rel := range g.Schema.Relations {
+		if rel.Type == "morph_to" {
+			lines = append(lines, "    "+g.morphCheck(rel))
+		}
+	}
+
+	sb.WriteString(strings.Join(lines, ",\n"))
+	sb.WriteString("\n);\n")
+
+	// Index
+	for _, idx := range g.Schema.Indexes {
+		var cols []string
+		for _, c := range idx.Columns {
+			cols = append(cols, quoteIdent(dialect, c))
+		}
+		unique := ""
+		if idx.Unique {
+			unique = "UNIQUE "
+		}
+		sb.WriteString("\n")
+		g.writeCreateIndex(&sb, unique, idx.Name, "("+strings.Join(cols, ", ")+")")
+	}
+
+	// Les requêtes filtrent systématiquement les enregistrements supprimés
+	if g.Schema.SoftDeletes {
+		sb.WriteString("\n")
+		g.writeCreateIndex(&sb, "", "idx_"+table+"_"+parser.SoftDeleteColumn, "("+quoteIdent(dialect, parser.SoftDeleteColumn)+")")
+	}
+
+	if g.search() {
+		g.writeSearchMigration(&sb)
+	}
+
+	return sb.String()
+}
+
+// writeCreateIndex écrit la création idempotente d'un index de la table (kind: "", "UNIQUE ",
+// "FULLTEXT "). MySQL ne connaît pas CREATE INDEX IF NOT EXISTS: l'index n'y est créé que
+// s'il est absent de information_schema.
+func (g *Generator) writeCreateIndex(sb *strings.Builder, kind, name, definition string) {
+	dialect := g.Config.Dialect
+	table := g.Schema.Table
+	if dialect != parser.DialectMySQL {
+		sb.WriteString(fmt.Sprintf("CREATE %sINDEX IF NOT EXISTS %s ON %s %s;\n",
+			kind, quoteIdent(dialect, name), quoteIdent(dialect, table), definition))
+		return
+	}
+	statement := fmt.Sprintf("CREATE %sINDEX %s ON %s %s", kind, quoteIdent(dialect, name), quoteIdent(dialect, table), definition)
+	sb.WriteString("SET @sql := IF((SELECT COUNT(*) FROM information_schema.statistics\n")
+	sb.WriteString(fmt.Sprintf("    WHERE table_schema = DATABASE() AND table_name = %s AND index_name = %s) = 0,\n", sqlLiteral(table), sqlLiteral(name)))
+	sb.WriteString(fmt.Sprintf("    %s, 'DO 0');\n", sqlLiteral(statement)))
+	sb.WriteString("PREPARE statement FROM @sql;\n")
+	sb.WriteString("EXECUTE statement;\n")
+	sb.WriteString("DEALLOCATE PREPARE statement;\n")
+}
+
+// foreignKey décrit une contrainte de clé étrangère portée par la table
+type foreignKey struct {
+	Column    string
+	RefTable  string
+	RefColumn string
+	OnDelete  string
+	OnUpdate  string
+}
+
+// foreignKeys retourne les clés étrangères de la table: ses relations belongs_to
+// et les relations has_many/has_one des autres schémas qui pointent vers elle
+func (g *Generator) foreignKeys() []foreignKey {
+	var fks []foreignKey
+	seen := map[string]int{}
+	add := func(fk foreignKey) {
+		if fk.Column == "" || !g.Schema.HasColumn(fk.Column) {
+			return
+		}
+		// Les deux côtés d'une relation décrivent la même contrainte: on complète ses actions
+		if i, ok := seen[fk.Column]; ok {
+			if fks[i].OnDelete == "" {
+				fks[i].OnDelete = fk.OnDelete
+			}
+			if fks[i].OnUpdate == "" {
+				fks[i].OnUpdate = fk.OnUpdate
+			}
+			return
+		}
+		seen[fk.Column] = len(fks)
+		fks = append(fks, fk)
+	}
+
+	for _, rel := range g.Schema.Relations {
+		if rel.Type != "belongs_to" {
+			continue
+		}
+		add(foreignKey{
+			Column:    rel.ForeignKey,
+			RefTable:  g.tableFor(rel.Model),
+			RefColumn: referencedColumn(rel),
+			OnDelete:  rel.OnDeleteSQL(),
+			OnUpdate:  rel.OnUpdateSQL(),
+		})
+	}
+
+	for _, schema := range g.Schemas {
+		if schema.Model == g.Schema.Model {
+			continue
+		}
+		for _, rel := range schema.Relations {
+			if (rel.Type != "has_many" && rel.Type != "has_one") || rel.Model != g.Schema.Model {
+				continue
+			}
+			add(foreignKey{
+				Column:    rel.ForeignKey,
+				RefTable:  schema.Table,
+				RefColumn: referencedColumn(rel),
+				OnDelete:  rel.OnDeleteSQL(),
+				OnUpdate:  rel.OnUpdateSQL(),
+			})
+		}
+	}
+
+	return fks
+}
+
+// referencedColumn retourne la colonne référencée par une relation ("id" par défaut)
+func referencedColumn(rel parser.Relation) string {
+	if rel.References != "" {
+		return rel.References
+	}
+	return "id"
+}
+
+// foreignKeyDefinition retourne la clause FOREIGN KEY d'une contrainte
+func (g *Generator) foreignKeyDefinition(fk foreignKey) string {
+	dialect := g.Config.Dialect
+	def := fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)",
+		quoteIdent(dialect, fk.Column),
+		quoteIdent(dialect, fk.RefTable),
+		quoteIdent(dialect, fk.RefColumn),
+	)
+	if fk.OnDelete != "" {
+		def += " ON DELETE " + fk.OnDelete
+	}
+	if fk.OnUpdate != "" {
+		def += " ON UPDATE " + fk.OnUpdate
+	}
+	return def
+}
+
+// morphCheck retourne la contrainte limitant le discriminant d'une relation morph_to
+// aux models déclarés
+func (g *Generator) morphCheck(rel parser.Relation) string {
+	var models []string
+	for _, model := range rel.Models {
+		models = append(models, sqlLiteral(model))
+	}
+	return fmt.Sprintf("CHECK (%s IN (%s))",
+		quoteIdent(g.Config.Dialect, rel.MorphTypeColumn()),
+		strings.Join(models, ", "),
+	)
+}
+
+// columnDefinition retourne la définition SQL d'une colonne
+func (g *Generator) columnDefinition(col parser.Column) string {
+	dialect := g.Config.Dialect
+	dbType := col.GetDBType(dialect)
+
+	parts := []string{quoteIdent(dialect, col.Name)}
+	if col.AutoIncrement {
+		switch dialect {
+		case parser.DialectPostgres:
+			if dbType == "bigint" {
+				dbType = "bigserial"
+			} else {
+				dbType = "serial"
+			}
+		case parser.DialectSQLite:
+			return strings.Join(append(parts, "integer PRIMARY KEY AUTOINCREMENT"), " ")
+		}
+	}
+	parts = append(parts, dbType)
+
+	if !col.Nullable {
+		parts = append(parts, "NOT NULL")
+	}
+	if col.AutoIncrement && dialect == parser.DialectMySQL {
+		parts = append(parts, "AUTO_INCREMENT")
+	}
+	if col.Unique && !col.Primary {
+		parts = append(parts, "UNIQUE")
+	}
+	if col.Default != nil {
+		parts = append(parts, "DEFAULT "+sqlLiteral(col.Default))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// quoteIdent protège un identifiant SQL selon le dialecte
+func quoteIdent(dialect, name string) string {
+	if dialect == parser.DialectMySQL {
+		return "`" + name + "`"
+	}
+	return `"` + name + `"`
+}
+
+// sqlLiteral convertit une valeur par défaut YAML en littéral SQL
+func sqlLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,282 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"go-scaffold/internal/inflection"
+	"go-scaffold/internal/parser"
+)
+
+// Les relations belongs_to vers un autre schéma du projet génèrent des routes imbriquées:
+// GET et POST /users/:id/posts, qui vérifient l'existence du parent (404) et filtrent ou
+// renseignent la clé étrangère depuis le chemin, et GET /posts/:id/user, qui retourne le
+// parent. Le parent est lu par le repository de l'enfant (FindUser), ce qui évite de
+// dépendre du repository du parent.
+
+// nestedRoutes contient les sous-routes GET déjà servies sous /:id
+var nestedRoutes = map[string]bool{"history": true, "ancestors": true, "descendants": true}
+
+// nestedRelation décrit une relation belongs_to exposée par des routes imbriquées
+type nestedRelation struct {
+	Relation parser.Relation
+	Parent   *parser.Schema
+	Key      parser.Column // Clé primaire du parent
+	Foreign  parser.Column // Clé étrangère de l'enfant
+}
+
+// Name retourne le nom de la relation (ex: user, author)
+func (n nestedRelation) Name() string {
+	if n.Relation.Name != "" {
+		return n.Relation.Name
+	}
+	return inflection.Snake(n.Relation.Model)
+}
+
+// Method retourne le suffixe des méthodes générées pour la relation (ex: User)
+func (n nestedRelation) Method() string {
+	return inflection.Pascal(n.Name())
+}
+
+// Resource retourne la ressource du parent dans les routes (ex: users)
+func (n nestedRelation) Resource() string {
+	return inflection.Pluralize(inflection.Snake(n.Parent.Model))
+}
+
+// Var retourne la variable de la clé étrangère lue depuis le chemin (ex: userID)
+func (n nestedRelation) Var() string {
+	return inflection.Camel(n.Foreign.Name)
+}
+
+// nestedRelations retourne les relations belongs_to exposées par des routes imbriquées: le
+// parent est un autre schéma du projet à clé simple, référencée par une clé étrangère non
+// nullable du même type
+func (g *Generator) nestedRelations() []nestedRelation {
+	var relations []nestedRelation
+	for _, rel := range g.Schema.Relations {
+		if rel.Type != "belongs_to" || rel.Model == g.Schema.Model {
+			continue
+		}
+		parent := g.findSchema(rel.Model)
+		if parent == nil || len(parent.PrimaryKeys()) != 1 {
+			continue
+		}
+		key := parent.PrimaryKeys()[0]
+		if referencedColumn(rel) != key.Name {
+			continue
+		}
+		var foreign *parser.Column
+		for i, col := range g.Schema.Columns {
+			if col.Name == rel.ForeignKey {
+				foreign = &g.Schema.Columns[i]
+			}
+		}
+		if foreign == nil || foreign.Nullable || foreign.BaseGoType() != key.BaseGoType() {
+			continue
+		}
+		relations = append(relations, nestedRelation{Relation: rel, Parent: parent, Key: key, Foreign: *foreign})
+	}
+	return relations
+}
+
+// nestedCollection indique si la liste et la création sous le parent sont générées: un
+// parent référencé par plusieurs relations (auteur, relecteur) rendrait le chemin ambigu
+func (g *Generator) nestedCollection(n nestedRelation) bool {
+	count := 0
+	for _, other := range g.nestedRelations() {
+		if other.Parent.Model == n.Parent.Model {
+			count++
+		}
+	}
+	return count == 1
+}
+
+// nestedFinderSignature retourne la signature de la lecture du parent dans le repository
+func (g *Generator) nestedFinderSignature(n nestedRelation) string {
+	return fmt.Sprintf("Find%s(%s) (*models.%s, error)", n.Method(), g.ctxParams(n.Var()+" "+n.Key.BaseGoType()), n.Parent.Model)
+}
+
+// writeNestedRepositoryInterface écrit les lectures des parents dans l'interface du repository
+func (g *Generator) writeNestedRepositoryInterface(sb *strings.Builder) {
+	for _, n := range g.nestedRelations() {
+		sb.WriteString(fmt.Sprintf("\t%s\n", g.nestedFinderSignature(n)))
+	}
+}
+
+// writeNestedRepository écrit les lectures des parents par leur clé
+func (g *Generator) writeNestedRepository(sb *strings.Builder) {
+	repoName := g.Schema.Model + "Repository"
+	for _, n := range g.nestedRelations() {
+		parentVar := inflection.Camel(n.Parent.Model)
+		sb.WriteString(fmt.Sprintf("// Find%s trouve le %s parent (%s) d'un %s par sa clé\n", n.Method(), parentVar, n.Name(), inflection.Camel(g.Schema.Model)))
+		sb.WriteString(fmt.Sprintf("func (r *%s) %s {\n", repoName, g.nestedFinderSignature(n)))
+		sb.WriteString(fmt.Sprintf("\tvar %s models.%s\n", parentVar, n.Parent.Model))
+		sb.WriteString(fmt.Sprintf("\terr := %s.Where(\"%s = ?\", %s).First(&%s).Error\n", g.repoDB(), n.Key.Name, n.Var(), parentVar))
+		sb.WriteString("\tif err != nil {\n")
+		sb.WriteString("\t\tif errors.Is(err, gorm.ErrRecordNotFound) {\n")
+		sb.WriteString("\t\t\treturn nil, errors.New(\"enregistrement non trouvé\")\n")
+		sb.WriteString("\t\t}\n")
+		sb.WriteString("\t\treturn nil, err\n")
+		sb.WriteString("\t}\n")
+		sb.WriteString(fmt.Sprintf("\treturn &%s, nil\n", parentVar))
+		sb.WriteString("}\n\n")
+	}
+}
+
+// writeNestedParent écrit la lecture de la clé du parent depuis le chemin et la vérification
+// de son existence (404)
+func (g *Generator) writeNestedParent(sb *strings.Builder, n nestedRelation) {
+	writeParamParsing(sb, n.Var(), n.Key.Name, n.Key.BaseGoType(), "return")
+	sb.WriteString(fmt.Sprintf("\tif _, err := %s; err != nil {\n", g.repoCall("Find"+n.Method(), n.Var())))
+	sb.WriteString("\t\tc.JSON(http.StatusNotFound, gin.H{\n")
+	sb.WriteString(fmt.Sprintf("\t\t\t\"error\": \"%s non trouvé\",\n", n.Parent.Model))
+	sb.WriteString("\t\t})\n")
+	sb.WriteString("\t\treturn\n")
+	sb.WriteString("\t}\n\n")
+}
+
+// writeNestedController écrit les handlers des routes imbriquées
+func (g *Generator) writeNestedController(sb *strings.Builder) {
+	modelName := g.Schema.Model
+	controllerName := modelName + "Controller"
+	varName := inflection.Camel(modelName)
+	pluralName := g.pluralVar()
+
+	for _, n := range g.nestedRelations() {
+		parentVar := inflection.Camel(n.Parent.Model)
+		route := fmt.Sprintf("/%s/{%s}/%s", n.Resource(), n.Key.Name, g.resourceName())
+
+		if g.nestedCollection(n) {
+			// Liste des enfants du parent
+			sb.WriteString(fmt.Sprintf("// IndexBy%s récupère la liste des %s d'un %s\n", n.Method(), pluralName, parentVar))
+			sb.WriteString(fmt.Sprintf("// @Summary Liste des %s d'un %s\n", pluralName, parentVar))
+			sb.WriteString(fmt.Sprintf("// @Description Récupère les %s d'un %s avec pagination, mêmes paramètres que la liste\n", pluralName, parentVar))
+			sb.WriteString("// @Tags " + modelName + "\n")
+			sb.WriteString("// @Produce json\n")
+			sb.WriteString(fmt.Sprintf("// @Param %s path %s true \"%s du %s\"\n", n.Key.Name, keyDocType(n.Key), n.Key.Name, parentVar))
+			sb.WriteString("// @Success 200 {object} map[string]interface{}\n")
+			sb.WriteString(fmt.Sprintf("// @Router %s [get]\n", route))
+			sb.WriteString(fmt.Sprintf("func (ctrl *%s) IndexBy%s(c *gin.Context) {\n", controllerName, n.Method()))
+			g.writeNestedParent(sb, n)
+			sb.WriteString("\tctrl.list(c, func(db *gorm.DB) *gorm.DB {\n")
+			sb.WriteString(fmt.Sprintf("\t\treturn db.Where(\"%s = ?\", %s)\n", n.Foreign.Name, n.Var()))
+			sb.WriteString("\t})\n")
+			sb.WriteString("}\n\n")
+
+			// Création sous le parent: la clé étrangère vient du chemin
+			sb.WriteString(fmt.Sprintf("// StoreFor%s crée un %s rattaché à un %s\n", n.Method(), varName, parentVar))
+			sb.WriteString(fmt.Sprintf("// @Summary Créer un %s d'un %s\n", varName, parentVar))
+			sb.WriteString(fmt.Sprintf("// @Description Crée un %s dont %s est lu depuis le chemin\n", varName, n.Foreign.Name))
+			sb.WriteString("// @Tags " + modelName + "\n")
+			sb.WriteString("// @Accept json\n")
+			sb.WriteString("// @Produce json\n")
+			sb.WriteString(fmt.Sprintf("// @Param %s path %s true \"%s du %s\"\n", n.Key.Name, keyDocType(n.Key), n.Key.Name, parentVar))
+			sb.WriteString(fmt.Sprintf("// @Param %s body requests.Create%sRequest true \"Données du %s\"\n", varName, modelName, varName))
+			sb.WriteString(fmt.Sprintf("// @Success 201 {object} models.%s\n", modelName))
+			sb.WriteString(fmt.Sprintf("// @Router %s [post]\n", route))
+			sb.WriteString(fmt.Sprintf("func (ctrl *%s) StoreFor%s(c *gin.Context) {\n", controllerName, n.Method()))
+			g.writeNestedParent(sb, n)
+			sb.WriteString(fmt.Sprintf("\tvar req requests.Create%sRequest\n\n", modelName))
+			sb.WriteString("\tif err := c.ShouldBindJSON(&req); err != nil {\n")
+			sb.WriteString("\t\tc.JSON(http.StatusBadRequest, gin.H{\n")
+			sb.WriteString("\t\t\t\"error\": \"Données invalides\",\n")
+			sb.WriteString("\t\t\t\"details\": err.Error(),\n")
+			sb.WriteString("\t\t})\n")
+			sb.WriteString("\t\treturn\n")
+			sb.WriteString("\t}\n")
+			if n.Foreign.IsZeroable() {
+				sb.WriteString(fmt.Sprintf("\treq.%s = &%s\n\n", inflection.Pascal(n.Foreign.Name), n.Var()))
+			} else {
+				sb.WriteString(fmt.Sprintf("\treq.%s = %s\n\n", inflection.Pascal(n.Foreign.Name), n.Var()))
+			}
+			sb.WriteString("\tif err := ctrl.validate.Struct(req); err != nil {\n")
+			sb.WriteString("\t\tc.JSON(http.StatusBadRequest, gin.H{\n")
+			sb.WriteString("\t\t\t\"error\": \"Validation échouée\",\n")
+			sb.WriteString("\t\t\t\"details\": err.Error(),\n")
+			sb.WriteString("\t\t})\n")
+			sb.WriteString("\t\treturn\n")
+			sb.WriteString("\t}\n\n")
+			sb.WriteString(fmt.Sprintf("\t%s := req.ToModel()\n\n", varName))
+			sb.WriteString(fmt.Sprintf("\tif err := %s; err != nil {\n", g.repoCall("Create", "&"+varName)))
+			sb.WriteString("\t\tc.JSON(http.StatusInternalServerError, gin.H{\n")
+			sb.WriteString("\t\t\t\"error\": \"Erreur lors de la création\",\n")
+			sb.WriteString("\t\t})\n")
+			sb.WriteString("\t\treturn\n")
+			sb.WriteString("\t}\n\n")
+			if g.Schema.Versioned {
+				g.writeETag(sb, varName)
+			}
+			sb.WriteString(fmt.Sprintf("\tc.JSON(http.StatusCreated, %s)\n", varName))
+			sb.WriteString("}\n\n")
+		}
+
+		if nestedRoutes[n.Name()] {
+			continue
+		}
+
+		// Parent d'un enregistrement
+		sb.WriteString(fmt.Sprintf("// Show%s récupère le %s parent (%s) d'un %s\n", n.Method(), parentVar, n.Name(), varName))
+		sb.WriteString(fmt.Sprintf("// @Summary %s d'un %s\n", n.Parent.Model, varName))
+		sb.WriteString(fmt.Sprintf("// @Description Récupère le %s référencé par %s\n", parentVar, n.Foreign.Name))
+		sb.WriteString("// @Tags " + modelName + "\n")
+		sb.WriteString("// @Produce json\n")
+		g.writeKeyDocParams(sb)
+		sb.WriteString(fmt.Sprintf("// @Success 200 {object} models.%s\n", n.Parent.Model))
+		sb.WriteString("// @Router /" + g.resourceName() + g.keyDocRoute() + "/" + n.Name() + " [get]\n")
+		sb.WriteString(fmt.Sprintf("func (ctrl *%s) Show%s(c *gin.Context) {\n", controllerName, n.Method()))
+		g.writeKeyParsing(sb)
+		sb.WriteString(fmt.Sprintf("\t%s, err := %s\n", varName, g.repoCall("FindByID", g.keyArgs())))
+		sb.WriteString("\tif err != nil {\n")
+		sb.WriteString("\t\tc.JSON(http.StatusNotFound, gin.H{\n")
+		sb.WriteString("\t\t\t\"error\": \"Enregistrement non trouvé\",\n")
+		sb.WriteString("\t\t})\n")
+		sb.WriteString("\t\treturn\n")
+		sb.WriteString("\t}\n\n")
+		sb.WriteString(fmt.Sprintf("\t%s, err := %s\n", parentVar, g.repoCall("Find"+n.Method(), varName+"."+inflection.Pascal(n.Foreign.Name))))
+		sb.WriteString("\tif err != nil {\n")
+		sb.WriteString("\t\tc.JSON(http.StatusNotFound, gin.H{\n")
+		sb.WriteString(fmt.Sprintf("\t\t\t\"error\": \"%s non trouvé\",\n", n.Parent.Model))
+		sb.WriteString("\t\t})\n")
+		sb.WriteString("\t\treturn\n")
+		sb.WriteString("\t}\n\n")
+		sb.WriteString(fmt.Sprintf("\tc.JSON(http.StatusOK, %s)\n", parentVar))
+		sb.WriteString("}\n\n")
+	}
+}
+
+// writeNestedRoutes enregistre les routes imbriquées: le parent d'un enregistrement dans le
+// groupe de la ressource, la liste et la création dans le groupe du parent
+func (g *Generator) writeNestedRoutes(sb *strings.Builder) {
+	varName := inflection.Camel(g.Schema.Model)
+	resourceName := g.resourceName()
+	keyRoute := g.keyRoute()
+	for _, n := range g.nestedRelations() {
+		if nestedRoutes[n.Name()] {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\t\t%sGroup.GET(\"%s/%s\", ctrl.Show%s) // GET /%s%s/%s\n",
+			varName, keyRoute, n.Name(), n.Method(), resourceName, keyRoute, n.Name()))
+	}
+}
+
+// writeNestedParentRoutes enregistre la liste et la création sous chaque parent
+func (g *Generator) writeNestedParentRoutes(sb *strings.Builder) {
+	resourceName := g.resourceName()
+	for _, n := range g.nestedRelations() {
+		if !g.nestedCollection(n) {
+			continue
+		}
+		group := inflection.Camel(n.Resource()) + "Group"
+		path := fmt.Sprintf("/:%s/%s", n.Key.Name, resourceName)
+		sb.WriteString(fmt.Sprintf("\n\t// Routes de %s imbriquées sous %s\n", resourceName, n.Resource()))
+		if g.Schema.TenantScoped {
+			sb.WriteString(fmt.Sprintf("\t%s := router.Group(\"/%s\", tenant.Middleware())\n", group, n.Resource()))
+		} else {
+			sb.WriteString(fmt.Sprintf("\t%s := router.Group(\"/%s\")\n", group, n.Resource()))
+		}
+		sb.WriteString("\t{\n")
+		sb.WriteString(fmt.Sprintf("\t\t%s.GET(\"%s\", ctrl.IndexBy%s)   // GET /%s%s\n", group, path, n.Method(), n.Resource(), path))
+		sb.WriteString(fmt.Sprintf("\t\t%s.POST(\"%s\", ctrl.StoreFor%s) // POST /%s%s\n", group, path, n.Method(), n.Resource(), path))
+		sb.WriteString("\t}\n")
+	}
+}
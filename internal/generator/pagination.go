@@ -0,0 +1,254 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go-scaffold/internal/inflection"
+)
+
+// Les schémas pagination: cursor paginent la liste par keyset: chaque page reprend après
+// (ou avant) la position de la dernière ligne lue, triée par la colonne du curseur puis par
+// la clé primaire. La position est transmise au client dans des curseurs opaques (JSON
+// encodé en base64url); le total n'est compté que si skip_total n'est pas demandé.
+
+// listParams retourne les paramètres de pagination de FindAll et des méthodes de scope
+func (g *Generator) listParams() string {
+	if g.Schema.CursorPagination() {
+		return "cursor string, pageSize int"
+	}
+	return "page, pageSize int"
+}
+
+// listArgs retourne les arguments de pagination transmis à FindAll
+func (g *Generator) listArgs() string {
+	if g.Schema.CursorPagination() {
+		return "cursor, pageSize"
+	}
+	return "page, pageSize"
+}
+
+// listResults retourne les résultats de FindAll et des méthodes de scope
+func (g *Generator) listResults() string {
+	if g.Schema.CursorPagination() {
+		return fmt.Sprintf("([]models.%s, Page, error)", g.Schema.Model)
+	}
+	return fmt.Sprintf("([]models.%s, int64, error)", g.Schema.Model)
+}
+
+// cursorType retourne le type de la position encodée dans les curseurs du model
+func (g *Generator) cursorType() string {
+	return inflection.Camel(g.Schema.Model) + "Cursor"
+}
+
+// GeneratePagination génère app/repositories/pagination.go, commun aux repositories
+// paginés par curseur
+func (g *Generator) GeneratePagination() error {
+	filename := filepath.Join("app", "repositories", "pagination.go")
+	return os.WriteFile(filename, []byte(paginationContent), 0644)
+}
+
+// writeCursorType écrit la position d'un curseur: colonne de tri et clé primaire
+func (g *Generator) writeCursorType(sb *strings.Builder) {
+	col, key := g.Schema.CursorColumn(), g.Schema.PrimaryKeys()[0]
+	sb.WriteString(fmt.Sprintf("// %s est la position encodée dans les curseurs de pagination\n", g.cursorType()))
+	sb.WriteString(fmt.Sprintf("type %s struct {\n", g.cursorType()))
+	var fields [][2]string
+	if col.Name != key.Name {
+		fields = append(fields, [2]string{inflection.Pascal(col.Name), fmt.Sprintf("%s `json:\"%s\"`", col.GetGoType(), col.Name)})
+	}
+	fields = append(fields, [2]string{inflection.Pascal(key.Name), fmt.Sprintf("%s `json:\"%s\"`", key.GetGoType(), key.Name)})
+	fields = append(fields, [2]string{"Prev", "bool `json:\"prev,omitempty\"`"})
+	writeAlignedFields(sb, alignTags(fields))
+	sb.WriteString("}\n\n")
+}
+
+// alignTags aligne les tags de champs "Type `tag`" comme le ferait gofmt
+func alignTags(fields [][2]string) [][2]string {
+	width := 0
+	for _, field := range fields {
+		if n := strings.Index(field[1], " "); n > width {
+			width = n
+		}
+	}
+	aligned := make([][2]string, len(fields))
+	for i, field := range fields {
+		n := strings.Index(field[1], " ")
+		aligned[i] = [2]string{field[0], field[1][:n] + strings.Repeat(" ", width-n) + field[1][n:]}
+	}
+	return aligned
+}
+
+// cursorWhere retourne la condition keyset qui sélectionne les lignes après la position
+// (op est > ou <) et ses arguments; GORM la met entre parenthèses parmi les autres conditions
+func (g *Generator) cursorWhere(op string) (string, string) {
+	col, key := g.Schema.CursorColumn(), g.Schema.PrimaryKeys()[0]
+	keyField := "position." + inflection.Pascal(key.Name)
+	if col.Name == key.Name {
+		return fmt.Sprintf("%s %s ?", key.Name, op), keyField
+	}
+	colField := "position." + inflection.Pascal(col.Name)
+	return fmt.Sprintf("%s %s ? OR (%s = ? AND %s %s ?)", col.Name, op, col.Name, key.Name, op),
+		fmt.Sprintf("%s, %s, %s", colField, colField, keyField)
+}
+
+// cursorOrder retourne l'ordre keyset, décroissant si desc
+func (g *Generator) cursorOrder(desc bool) string {
+	col, key := g.Schema.CursorColumn(), g.Schema.PrimaryKeys()[0]
+	columns := []string{col.Name}
+	if col.Name != key.Name {
+		columns = append(columns, key.Name)
+	}
+	for i := range columns {
+		if desc {
+			columns[i] += " DESC"
+		}
+	}
+	return strings.Join(columns, ", ")
+}
+
+// cursorOf retourne l'expression du curseur d'un model (varName) dans le sens indiqué
+func (g *Generator) cursorOf(varName string, prev bool) string {
+	col, key := g.Schema.CursorColumn(), g.Schema.PrimaryKeys()[0]
+	var fields []string
+	if col.Name != key.Name {
+		fields = append(fields, fmt.Sprintf("%s: %s.%s", inflection.Pascal(col.Name), varName, inflection.Pascal(col.Name)))
+	}
+	fields = append(fields, fmt.Sprintf("%s: %s.%s", inflection.Pascal(key.Name), varName, inflection.Pascal(key.Name)))
+	if prev {
+		fields = append(fields, "Prev: true")
+	}
+	return fmt.Sprintf("encodeCursor(%s{%s})", g.cursorType(), strings.Join(fields, ", "))
+}
+
+// writeCursorFindAll écrit le corps de FindAll d'un schéma paginé par curseur
+func (g *Generator) writeCursorFindAll(sb *strings.Builder) {
+	modelName := g.Schema.Model
+	pluralName := g.pluralVar()
+	desc := g.Schema.CursorDescending()
+	forward, backward := ">", "<"
+	if desc {
+		forward, backward = "<", ">"
+	}
+
+	sb.WriteString("\tpage := Page{PageSize: pageSize}\n")
+	sb.WriteString(fmt.Sprintf("\tvar position %s\n", g.cursorType()))
+	sb.WriteString("\tif cursor != \"\" {\n")
+	sb.WriteString("\t\tif err := decodeCursor(cursor, &position); err != nil {\n")
+	sb.WriteString("\t\t\treturn nil, page, err\n")
+	sb.WriteString("\t\t}\n")
+	sb.WriteString("\t}\n\n")
+
+	if !g.Schema.Pagination.SkipTotal {
+		sb.WriteString("\t// Compter le total\n")
+		sb.WriteString("\tvar total int64\n")
+		sb.WriteString(fmt.Sprintf("\tif err := %s.Model(&models.%s{}).Scopes(scopes...).Count(&total).Error; err != nil {\n", g.repoDB(), modelName))
+		sb.WriteString("\t\treturn nil, page, err\n")
+		sb.WriteString("\t}\n")
+		sb.WriteString("\tpage.Total = &total\n\n")
+	}
+
+	sb.WriteString("\t// Lignes suivant la position du curseur, ou la précédant pour un curseur prev\n")
+	sb.WriteString("\t// (parcourues à rebours puis remises dans l'ordre)\n")
+	sb.WriteString(fmt.Sprintf("\tquery := %s.Scopes(scopes...)\n", g.repoDB()))
+	sb.WriteString("\tbackward := cursor != \"\" && position.Prev\n")
+	sb.WriteString("\tswitch {\n")
+	where, args := g.cursorWhere(backward)
+	sb.WriteString("\tcase backward:\n")
+	sb.WriteString(fmt.Sprintf("\t\tquery = query.Where(\"%s\", %s).Order(\"%s\")\n", where, args, g.cursorOrder(!desc)))
+	where, args = g.cursorWhere(forward)
+	sb.WriteString("\tcase cursor != \"\":\n")
+	sb.WriteString(fmt.Sprintf("\t\tquery = query.Where(\"%s\", %s).Order(\"%s\")\n", where, args, g.cursorOrder(desc)))
+	sb.WriteString("\tdefault:\n")
+	sb.WriteString(fmt.Sprintf("\t\tquery = query.Order(\"%s\")\n", g.cursorOrder(desc)))
+	sb.WriteString("\t}\n\n")
+
+	sb.WriteString(fmt.Sprintf("\tvar %s []models.%s\n", pluralName, modelName))
+	sb.WriteString(fmt.Sprintf("\tif err := query.Limit(pageSize + 1).Find(&%s).Error; err != nil {\n", pluralName))
+	sb.WriteString("\t\treturn nil, page, err\n")
+	sb.WriteString("\t}\n")
+	sb.WriteString(fmt.Sprintf("\tmore := len(%s) > pageSize\n", pluralName))
+	sb.WriteString("\tif more {\n")
+	sb.WriteString(fmt.Sprintf("\t\t%s = %s[:pageSize]\n", pluralName, pluralName))
+	sb.WriteString("\t}\n")
+	sb.WriteString("\tif backward {\n")
+	sb.WriteString(fmt.Sprintf("\t\tfor i, j := 0, len(%s)-1; i < j; i, j = i+1, j-1 {\n", pluralName))
+	sb.WriteString(fmt.Sprintf("\t\t\t%s[i], %s[j] = %s[j], %s[i]\n", pluralName, pluralName, pluralName, pluralName))
+	sb.WriteString("\t\t}\n")
+	sb.WriteString("\t}\n\n")
+
+	sb.WriteString(fmt.Sprintf("\tif len(%s) > 0 {\n", pluralName))
+	sb.WriteString(fmt.Sprintf("\t\tfirst, last := %s[0], %s[len(%s)-1]\n", pluralName, pluralName, pluralName))
+	sb.WriteString("\t\tif more || backward {\n")
+	sb.WriteString(fmt.Sprintf("\t\t\tpage.NextCursor = %s\n", g.cursorOf("last", false)))
+	sb.WriteString("\t\t}\n")
+	sb.WriteString("\t\tif (more && backward) || (cursor != \"\" && !backward) {\n")
+	sb.WriteString(fmt.Sprintf("\t\t\tpage.PrevCursor = %s\n", g.cursorOf("first", true)))
+	sb.WriteString("\t\t}\n")
+	sb.WriteString("\t}\n")
+	sb.WriteString(fmt.Sprintf("\treturn %s, page, nil\n", pluralName))
+}
+
+// writeCursorIndex écrit la fin de Index d'un schéma paginé par curseur: appel de FindAll
+// (args) et réponse
+func (g *Generator) writeCursorIndex(sb *strings.Builder, args string) {
+	pluralName := g.pluralVar()
+	sb.WriteString(fmt.Sprintf("\t%s, page, err := %s\n", pluralName, g.repoCall("FindAll", args)))
+	sb.WriteString("\tif errors.Is(err, repositories.ErrInvalidCursor) {\n")
+	sb.WriteString("\t\tc.JSON(http.StatusBadRequest, gin.H{\n")
+	sb.WriteString("\t\t\t\"error\": \"Curseur de pagination invalide\",\n")
+	sb.WriteString("\t\t})\n")
+	sb.WriteString("\t\treturn\n")
+	sb.WriteString("\t}\n")
+	sb.WriteString("\tif err != nil {\n")
+	sb.WriteString("\t\tc.JSON(http.StatusInternalServerError, gin.H{\n")
+	sb.WriteString("\t\t\t\"error\": \"Erreur lors de la récupération des données\",\n")
+	sb.WriteString("\t\t})\n")
+	sb.WriteString("\t\treturn\n")
+	sb.WriteString("\t}\n\n")
+	sb.WriteString("\tc.JSON(http.StatusOK, gin.H{\n")
+	sb.WriteString(fmt.Sprintf("\t\t\"data\": selection.Filter(%s),\n", pluralName))
+	sb.WriteString("\t\t\"pagination\": page,\n")
+	sb.WriteString("\t})\n")
+}
+
+// paginationContent contient les curseurs partagés par les repositories des projets générés
+const paginationContent = `package repositories
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidCursor est renvoyée pour un curseur de pagination illisible
+var ErrInvalidCursor = errors.New("curseur de pagination invalide")
+
+// Page décrit une page d'une liste paginée par curseur
+type Page struct {
+	PageSize   int    ` + "`" + `json:"page_size"` + "`" + `
+	NextCursor string ` + "`" + `json:"next_cursor,omitempty"` + "`" + ` // Absent sur la dernière page
+	PrevCursor string ` + "`" + `json:"prev_cursor,omitempty"` + "`" + ` // Absent sur la première page
+	Total      *int64 ` + "`" + `json:"total,omitempty"` + "`" + `       // Absent avec skip_total
+}
+
+// encodeCursor encode une position en curseur opaque
+func encodeCursor(position interface{}) string {
+	data, _ := json.Marshal(position)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor décode un curseur dans la position attendue par le repository
+func decodeCursor(cursor string, position interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, position); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
+`
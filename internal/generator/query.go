@@ -0,0 +1,316 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go-scaffold/internal/parser"
+)
+
+// Les colonnes filterable: true et sortable: true forment la liste blanche des paramètres
+// ?filter[colonne][opérateur]=valeur et ?sort=-colonne,... de la liste. Le package
+// app/query convertit chaque valeur selon le type de la colonne et construit des scopes
+// GORM; un champ ou un opérateur inconnu est rejeté (400), y compris sur les listes sans
+// colonne filtrable ni triable, dont les listes blanches sont vides.
+
+// queryKinds associe les familles de filtre du parser aux constantes de app/query
+var queryKinds = map[string]string{
+	parser.FilterString:  "query.String",
+	parser.FilterInteger: "query.Integer",
+	parser.FilterDecimal: "query.Decimal",
+	parser.FilterBoolean: "query.Boolean",
+	parser.FilterTime:    "query.Time",
+}
+
+// queryFilters retourne le nom de la liste des colonnes filtrables du model
+func (g *Generator) queryFilters() string {
+	return g.Schema.Model + "Filters"
+}
+
+// querySorts retourne le nom de la liste des colonnes triables du model
+func (g *Generator) querySorts() string {
+	return g.Schema.Model + "Sorts"
+}
+
+// GenerateQuery génère le package app/query
+func (g *Generator) GenerateQuery() error {
+	filename := filepath.Join("app", "query", "query.go")
+
+	// Créer le répertoire s'il n'existe pas
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, []byte(queryPackageContent), 0644)
+}
+
+// writeModelQuery écrit les listes blanches des colonnes filtrables et triables
+func (g *Generator) writeModelQuery(sb *strings.Builder) {
+	pluralName := g.pluralVar()
+
+	sb.WriteString(fmt.Sprintf("// %s liste les colonnes filtrables des %s (?filter[colonne][opérateur]=valeur)\n", g.queryFilters(), pluralName))
+	sb.WriteString(fmt.Sprintf("var %s = query.Fields{", g.queryFilters()))
+	if len(g.Schema.FilterableColumns()) > 0 {
+		sb.WriteString("\n")
+	}
+	for _, col := range g.Schema.FilterableColumns() {
+		if col.Nullable {
+			sb.WriteString(fmt.Sprintf("\t%q: {Kind: %s, Nullable: true},\n", col.Name, queryKinds[col.FilterKind()]))
+		} else {
+			sb.WriteString(fmt.Sprintf("\t%q: {Kind: %s},\n", col.Name, queryKinds[col.FilterKind()]))
+		}
+	}
+	sb.WriteString("}\n\n")
+
+	var sorts []string
+	for _, col := range g.Schema.SortableColumns() {
+		sorts = append(sorts, fmt.Sprintf("%q", col.Name))
+	}
+	sb.WriteString(fmt.Sprintf("// %s liste les colonnes triables des %s (?sort=-colonne,colonne)\n", g.querySorts(), pluralName))
+	sb.WriteString(fmt.Sprintf("var %s = []string{%s}\n\n", g.querySorts(), strings.Join(sorts, ", ")))
+}
+
+// writeQueryDocParams écrit la documentation des paramètres filter et sort de la liste
+func (g *Generator) writeQueryDocParams(sb *strings.Builder) {
+	var filters, sorts []string
+	for _, col := range g.Schema.FilterableColumns() {
+		filters = append(filters, col.Name)
+	}
+	for _, col := range g.Schema.SortableColumns() {
+		sorts = append(sorts, col.Name)
+	}
+	if len(filters) > 0 {
+		sb.WriteString(fmt.Sprintf("// @Param filter query string false \"Filtres filter[colonne][opérateur]=valeur (%s)\"\n", strings.Join(filters, ", ")))
+	}
+	if len(sorts) > 0 {
+		sb.WriteString(fmt.Sprintf("// @Param sort query string false \"Tri, - pour décroissant (%s)\"\n", strings.Join(sorts, ", ")))
+	}
+}
+
+// writeQuerySelection écrit, dans Index, la traduction des filtres et du tri en scopes
+func (g *Generator) writeQuerySelection(sb *strings.Builder) {
+	sb.WriteString("\t// Filtres (?filter[status]=published&filter[view_count][gte]=10) et tri (?sort=-published_at,title)\n")
+	sb.WriteString(fmt.Sprintf("\tfilters, err := query.Filters(c.Request.URL.Query(), models.%s)\n", g.queryFilters()))
+	g.writeQueryError(sb)
+	sb.WriteString(fmt.Sprintf("\torder, err := query.Sort(c.Query(\"sort\"), models.%s)\n", g.querySorts()))
+	g.writeQueryError(sb)
+	sb.WriteString("\tscopes = append(append(scopes, filters...), order)\n\n")
+}
+
+func (g *Generator) writeQueryError(sb *strings.Builder) {
+	sb.WriteString("\tif err != nil {\n")
+	sb.WriteString("\t\tc.JSON(http.StatusBadRequest, gin.H{\n")
+	sb.WriteString("\t\t\t\"error\": err.Error(),\n")
+	sb.WriteString("\t\t})\n")
+	sb.WriteString("\t\treturn\n")
+	sb.WriteString("\t}\n")
+}
+
+// queryPackageContent contient le package de filtres et de tri des projets générés
+const queryPackageContent = `package query
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Kind est la famille d'une colonne filtrable: elle détermine la conversion des valeurs
+// et les opérateurs admis
+type Kind int
+
+const (
+	String Kind = iota
+	Integer
+	Decimal
+	Boolean
+	Time
+)
+
+// Field décrit une colonne filtrable
+type Field struct {
+	Kind     Kind
+	Nullable bool // Autorise filter[colonne][null]=true|false
+}
+
+// Fields associe les colonnes filtrables d'un model à leur description
+type Fields map[string]Field
+
+// Error signale un filtre ou un tri invalide, à renvoyer en 400
+type Error struct {
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// operators contient les opérateurs admis par famille (eq par défaut)
+var operators = map[Kind][]string{
+	String:  {"eq", "ne", "like", "in"},
+	Integer: {"eq", "ne", "gt", "gte", "lt", "lte", "in"},
+	Decimal: {"eq", "ne", "gt", "gte", "lt", "lte", "in"},
+	Boolean: {"eq", "ne"},
+	Time:    {"eq", "ne", "gt", "gte", "lt", "lte"},
+}
+
+// filterKey reconnaît filter[colonne] et filter[colonne][opérateur]
+var filterKey = regexp.MustCompile(` + "`" + `^filter\[([A-Za-z0-9_]+)\](?:\[([a-z]+)\])?$` + "`" + `)
+
+// Filters traduit les paramètres filter[colonne][opérateur]=valeur en scopes GORM.
+// Seules les colonnes de fields sont admises; in attend des valeurs séparées par des
+// virgules, like un motif SQL (%), null true ou false.
+func Filters(values url.Values, fields Fields) ([]func(*gorm.DB) *gorm.DB, error) {
+	var keys []string
+	for key := range values {
+		if strings.HasPrefix(key, "filter[") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var scopes []func(*gorm.DB) *gorm.DB
+	for _, key := range keys {
+		match := filterKey.FindStringSubmatch(key)
+		if match == nil {
+			return nil, &Error{Message: "Filtre invalide: " + key}
+		}
+		column, op := match[1], match[2]
+		if op == "" {
+			op = "eq"
+		}
+		field, ok := fields[column]
+		if !ok {
+			return nil, &Error{Message: "Filtre inconnu: " + column}
+		}
+
+		expr, err := condition(column, op, field, values.Get(key))
+		if err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Where(expr)
+		})
+	}
+	return scopes, nil
+}
+
+// condition construit la condition d'un filtre après conversion de sa valeur
+func condition(column, op string, field Field, raw string) (clause.Expression, error) {
+	col := clause.Column{Table: clause.CurrentTable, Name: column}
+
+	if op == "null" {
+		isNull, err := strconv.ParseBool(raw)
+		if !field.Nullable || err != nil {
+			return nil, &Error{Message: fmt.Sprintf("Opérateur null non supporté pour %s", column)}
+		}
+		if isNull {
+			return clause.Eq{Column: col, Value: nil}, nil
+		}
+		return clause.Neq{Column: col, Value: nil}, nil
+	}
+
+	allowed := false
+	for _, candidate := range operators[field.Kind] {
+		allowed = allowed || candidate == op
+	}
+	if !allowed {
+		return nil, &Error{Message: fmt.Sprintf("Opérateur %s non supporté pour %s", op, column)}
+	}
+
+	if op == "in" {
+		var values []interface{}
+		for _, item := range strings.Split(raw, ",") {
+			value, err := parse(field.Kind, strings.TrimSpace(item))
+			if err != nil {
+				return nil, &Error{Message: fmt.Sprintf("Valeur invalide pour %s: %s", column, item)}
+			}
+			values = append(values, value)
+		}
+		return clause.IN{Column: col, Values: values}, nil
+	}
+
+	value, err := parse(field.Kind, raw)
+	if err != nil {
+		return nil, &Error{Message: fmt.Sprintf("Valeur invalide pour %s: %s", column, raw)}
+	}
+	switch op {
+	case "ne":
+		return clause.Neq{Column: col, Value: value}, nil
+	case "gt":
+		return clause.Gt{Column: col, Value: value}, nil
+	case "gte":
+		return clause.Gte{Column: col, Value: value}, nil
+	case "lt":
+		return clause.Lt{Column: col, Value: value}, nil
+	case "lte":
+		return clause.Lte{Column: col, Value: value}, nil
+	case "like":
+		return clause.Like{Column: col, Value: value}, nil
+	}
+	return clause.Eq{Column: col, Value: value}, nil
+}
+
+// parse convertit une valeur de filtre dans le type de sa colonne
+func parse(kind Kind, raw string) (interface{}, error) {
+	switch kind {
+	case Integer:
+		return strconv.ParseInt(raw, 10, 64)
+	case Decimal:
+		// La valeur est vérifiée puis transmise telle quelle, sans perte de précision
+		if _, err := strconv.ParseFloat(raw, 64); err != nil {
+			return nil, err
+		}
+		return raw, nil
+	case Boolean:
+		return strconv.ParseBool(raw)
+	case Time:
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			return t, nil
+		}
+		return time.Parse("2006-01-02", raw)
+	}
+	return raw, nil
+}
+
+// Sort traduit ?sort=-published_at,title en scope de tri; seules les colonnes de
+// sortable sont admises, un - en tête inverse l'ordre
+func Sort(param string, sortable []string) (func(*gorm.DB) *gorm.DB, error) {
+	var columns []clause.OrderByColumn
+	for _, part := range strings.Split(param, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name := strings.TrimPrefix(part, "-")
+		known := false
+		for _, candidate := range sortable {
+			known = known || candidate == name
+		}
+		if !known {
+			return nil, &Error{Message: "Tri inconnu: " + name}
+		}
+		columns = append(columns, clause.OrderByColumn{
+			Column: clause.Column{Table: clause.CurrentTable, Name: name},
+			Desc:   strings.HasPrefix(part, "-"),
+		})
+	}
+
+	return func(db *gorm.DB) *gorm.DB {
+		// Le comptage de la pagination (Count) se passe du tri, que PostgreSQL refuserait
+		if _, counting := db.Statement.Dest.(*int64); counting || len(columns) == 0 {
+			return db
+		}
+		return db.Order(clause.OrderBy{Columns: columns})
+	}, nil
+}
+`
@@ -0,0 +1,120 @@
+package generator
+
+import (
+	"testing"
+
+	"go-scaffold/internal/config"
+	"go-scaffold/internal/parser"
+)
+
+// listSchemas déclarent des auteurs et leurs posts filtrables, triables et recherchables
+var listSchemas = []string{`
+table: authors
+model: Author
+columns: [{name: id, type: bigint, primary: true, auto_increment: true}, {name: name, type: string}]
+relations: [{type: has_many, model: Post, foreign_key: author_id}]`, `
+table: posts
+model: Post
+columns:
+  - {name: id, type: bigint, primary: true, auto_increment: true}
+  - {name: title, type: string, size: 200, filterable: true, sortable: true, searchable: true}
+  - {name: body, type: text, searchable: true}
+  - {name: views, type: integer, filterable: true, sortable: true}
+  - {name: token, type: string, hidden: true}
+  - {name: author_id, type: bigint}
+relations: [{type: belongs_to, model: Author, foreign_key: author_id}]`}
+
+func TestGenerateFilters(t *testing.T) {
+	files := generate(t, nil, listSchemas...)
+
+	contains(t, files, "app/models/post.go",
+		"var PostFilters = query.Fields{",
+		`"views": {Kind: query.Integer},`,
+		`var PostSorts = []string{"title", "views"}`,
+	)
+	contains(t, files, "app/controllers/post_controller.go", "query.Filters(", "query.Sort(")
+	// Une liste sans colonne filtrable rejette quand même les filtres inconnus
+	contains(t, files, "app/controllers/author_controller.go", "query.Filters(")
+	contains(t, files, "app/query/query.go", "package query")
+}
+
+func TestGenerateCursorPagination(t *testing.T) {
+	files := generate(t, nil, `
+table: events
+model: Event
+pagination: {mode: cursor, column: created_at, order: desc}
+columns:
+  - {name: id, type: bigint, primary: true, auto_increment: true}
+  - {name: created_at, type: timestamp}`)
+
+	contains(t, files, "app/repositories/event_repository.go",
+		"type eventCursor struct",
+		"created_at < ? OR (created_at = ? AND id < ?)",
+		"created_at DESC, id DESC",
+	)
+	contains(t, files, "app/controllers/event_controller.go", `c.Query("cursor")`)
+}
+
+func TestGenerateSearch(t *testing.T) {
+	tests := []struct {
+		dialect   string
+		model     string
+		migration string
+	}{
+		{parser.DialectPostgres, "websearch_to_tsquery('simple', ?)", "USING GIN (to_tsvector('simple', coalesce(\"title\", '') || ' ' || coalesce(\"body\", '')))"},
+		{parser.DialectMySQL, "MATCH(title, body) AGAINST(? IN NATURAL LANGUAGE MODE)", "FULLTEXT"},
+		{parser.DialectSQLite, "posts_fts MATCH ?", "content='posts', content_rowid='id');"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Dialect = tt.dialect
+			files := generate(t, cfg, listSchemas...)
+
+			contains(t, files, "app/models/post.go", "func ScopePostSearch(q string) func(*gorm.DB) *gorm.DB {", tt.model)
+			contains(t, files, "app/repositories/post_repository.go", ") Search(")
+			contains(t, files, "database/migrations/create_posts_table.sql", tt.migration)
+			contains(t, files, "app/controllers/post_controller.go", `c.Query("q")`)
+		})
+	}
+}
+
+func TestGenerateInclude(t *testing.T) {
+	schemas := append([]string{}, listSchemas...)
+	schemas[1] = "tenant_scoped: true\n" + schemas[1]
+	files := generate(t, nil, schemas...)
+
+	contains(t, files, "app/controllers/author_controller.go", "include.Parse(c.Request.URL.Query(), models.Author{}, models.AuthorIncludes)")
+	contains(t, files, "app/models/author.go", "var AuthorIncludes = []string{}")
+	// Les posts inclus sont restreints au tenant: la liste et le détail l'exigent
+	contains(t, files, "routes/author_routes.go",
+		`.GET("", tenant.Middleware(), ctrl.Index)`,
+		`.GET("/:id", tenant.Middleware(), ctrl.Show)`,
+	)
+	excludes(t, files, "routes/author_routes.go", `.POST("", tenant.Middleware()`)
+}
+
+func TestGenerateFieldset(t *testing.T) {
+	files := generate(t, nil, listSchemas...)
+
+	contains(t, files, "app/models/post.go",
+		"Token string `json:\"-\"",
+		"var PostFields = fieldset.Set{",
+	)
+	excludes(t, files, "app/models/post.go", `"id", "title", "body", "views", "token"`)
+	contains(t, files, "app/controllers/post_controller.go", "fieldset.Parse(c.Request.URL.Query(), models.PostFields)")
+	contains(t, files, "app/requests/post_request.go", "Token string")
+}
+
+func TestGenerateNestedRoutes(t *testing.T) {
+	files := generate(t, nil, listSchemas...)
+
+	contains(t, files, "routes/post_routes.go",
+		`authorsGroup.GET("/:id/posts", ctrl.IndexByAuthor)`,
+		`authorsGroup.POST("/:id/posts", ctrl.StoreForAuthor)`,
+	)
+	contains(t, files, "app/controllers/post_controller.go",
+		"func (ctrl *PostController) IndexByAuthor(c *gin.Context)",
+		"func (ctrl *PostController) StoreForAuthor(c *gin.Context)",
+	)
+}
@@ -0,0 +1,113 @@
+package generator
+
+import (
+	"testing"
+
+	"go-scaffold/internal/config"
+)
+
+func TestGenerateCompositeKey(t *testing.T) {
+	files := generate(t, nil, `
+table: order_lines
+model: OrderLine
+columns:
+  - {name: order_id, type: string, size: 36, primary: true}
+  - {name: line_no, type: integer, primary: true}
+  - {name: quantity, type: integer}`)
+
+	contains(t, files, "app/models/order_line.go", "LineNo int `json:\"line_no\" gorm:\"primaryKey;")
+	contains(t, files, "routes/order_line_routes.go", `"/:order_id/:line_no"`)
+	contains(t, files, "app/repositories/order_line_repository.go", `"order_id = ? AND line_no = ?"`)
+	contains(t, files, "app/models/order_line.go", "OrderID string `json:\"order_id\" gorm:\"primaryKey;")
+	contains(t, files, "app/repositories/order_line_repository.go",
+		"FindByID(ctx context.Context, orderID string, lineNo int",
+	)
+	contains(t, files, "database/migrations/create_order_lines_table.sql", `PRIMARY KEY ("order_id", "line_no")`)
+}
+
+func TestGenerateReferentialActions(t *testing.T) {
+	files := generate(t, nil, `
+table: users
+model: User
+columns: [{name: id, type: bigint, primary: true, auto_increment: true}]`, `
+table: posts
+model: Post
+columns:
+  - {name: id, type: bigint, primary: true, auto_increment: true}
+  - {name: user_id, type: bigint, nullable: true}
+relations: [{type: belongs_to, model: User, foreign_key: user_id, on_delete: set null, on_update: cascade}]`)
+
+	contains(t, files, "app/models/post.go", "constraint:OnDelete:SET NULL,OnUpdate:CASCADE")
+	contains(t, files, "database/migrations/create_posts_table.sql",
+		`FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE SET NULL ON UPDATE CASCADE`,
+	)
+}
+
+func TestGenerateMorph(t *testing.T) {
+	files := generate(t, nil, `
+table: posts
+model: Post
+columns: [{name: id, type: uuid, primary: true}]
+relations: [{type: morph_many, model: Comment, name: commentable}]`, `
+table: comments
+model: Comment
+columns: [{name: id, type: bigint, primary: true, auto_increment: true}, {name: body, type: text}]
+relations: [{type: morph_to, name: commentable, models: [Post]}]`)
+
+	contains(t, files, "app/models/comment.go", "CommentableType string")
+	contains(t, files, "app/repositories/comment_repository.go", ") FindByCommentable(")
+	contains(t, files, "app/requests/comment_request.go", "oneof=Post")
+	contains(t, files, "app/models/post.go",
+		"polymorphic:Commentable;polymorphicType:CommentableType;polymorphicId:CommentableID;polymorphicValue:Post",
+	)
+	contains(t, files, "app/models/comment.go", "CommentableID string")
+	contains(t, files, "app/repositories/comment_repository.go", "commentableType string, commentableID string")
+}
+
+func TestGenerateTree(t *testing.T) {
+	cfg := config.Default()
+	files := generate(t, cfg, `
+table: categories
+model: Category
+tree: true
+columns:
+  - {name: id, type: bigint, primary: true, auto_increment: true}
+  - {name: name, type: string}
+  - {name: parent_id, type: bigint, nullable: true}
+relations:
+  - {type: belongs_to, model: Category, name: parent, foreign_key: parent_id}
+  - {type: has_many, model: Category, name: children, foreign_key: parent_id}`)
+
+	contains(t, files, "app/models/category.go",
+		"Parent *Category `json:\"parent,omitempty\" gorm:\"foreignKey:parent_id\"`",
+		"Children []Category `json:\"children,omitempty\" gorm:\"foreignKey:parent_id\"`",
+	)
+	contains(t, files, "app/repositories/category_repository.go",
+		") Ancestors(",
+		") Descendants(",
+		"WITH RECURSIVE",
+		"ErrCategoryCycle",
+	)
+	contains(t, files, "routes/category_routes.go", `"/:id/ancestors"`, `"/:id/descendants"`)
+}
+
+func TestGenerateNamedRelations(t *testing.T) {
+	files := generate(t, nil, `
+table: users
+model: User
+columns: [{name: id, type: bigint, primary: true, auto_increment: true}]`, `
+table: posts
+model: Post
+columns:
+  - {name: id, type: bigint, primary: true, auto_increment: true}
+  - {name: author_id, type: bigint}
+  - {name: reviewer_id, type: bigint, nullable: true}
+relations:
+  - {type: belongs_to, model: User, name: author, foreign_key: author_id}
+  - {type: belongs_to, model: User, name: reviewer, foreign_key: reviewer_id}`)
+
+	contains(t, files, "app/models/post.go",
+		"Author *User `json:\"author,omitempty\" gorm:\"foreignKey:author_id\"`",
+		"Reviewer *User `json:\"reviewer,omitempty\" gorm:\"foreignKey:reviewer_id\"`",
+	)
+}
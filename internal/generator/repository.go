@@ -5,12 +5,16 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"go-scaffold/internal/config"
+	"go-scaffold/internal/inflection"
+	"go-scaffold/internal/parser"
 )
 
 // GenerateRepository génère le fichier repository
 func (g *Generator) GenerateRepository() error {
 	modelName := g.Schema.Model
-	filename := filepath.Join("app", "repositories", toSnakeCase(modelName)+"_repository.go")
+	filename := filepath.Join("app", "repositories", inflection.Snake(modelName)+"_repository.go")
 
 	// Créer le répertoire s'il n'existe pas
 	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
@@ -18,44 +22,96 @@ func (g *Generator) GenerateRepository() error {
 	}
 
 	content := g.generateRepositoryContent()
-	return os.WriteFile(filename, []byte(content), 0644)
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		return err
+	}
+
+	if g.Schema.CursorPagination() {
+		return g.GeneratePagination()
+	}
+	return nil
 }
 
 func (g *Generator) generateRepositoryContent() string {
 	var sb strings.Builder
 	modelName := g.Schema.Model
 	repoName := modelName + "Repository"
-	varName := toCamelCase(modelName)
+	varName := inflection.Camel(modelName)
+	pluralName := g.pluralVar()
 
 	sb.WriteString("package repositories\n\n")
-	sb.WriteString("import (\n")
-	sb.WriteString("\t\"errors\"\n")
-	sb.WriteString("\t\"app/models\"\n")
-	sb.WriteString("\t\"config\"\n\n")
-	sb.WriteString("\t\"gorm.io/gorm\"\n")
-	sb.WriteString(")\n\n")
+	imports := append([]string{"errors", "app/models", "gorm.io/gorm"}, g.scopeImports()...)
+	if !g.legacyRepositories() || g.contextual() {
+		imports = append(imports, "context")
+	}
+	if g.Schema.Audit {
+		imports = append(imports, "app/audit")
+	}
+	if g.Schema.TenantScoped {
+		imports = append(imports, "app/tenant")
+	}
+	if g.Schema.CursorPagination() {
+		cursorColumn := g.Schema.CursorColumn()
+		imports = append(imports, cursorColumn.BaseImports()...)
+	}
+	writeImports(&sb, imports...)
+
+	if g.Schema.Tree {
+		g.writeTreeRepositoryError(&sb)
+	}
+	if g.Schema.Versioned {
+		g.writeVersionRepositoryError(&sb)
+	}
+	if g.Schema.SoftDeletes {
+		g.writeSoftDeleteRepositoryError(&sb)
+	}
+	if g.Schema.CursorPagination() {
+		g.writeCursorType(&sb)
+	}
 
 	// Interface du repository
 	sb.WriteString(fmt.Sprintf("// %sInterface définit les méthodes du repository\n", modelName))
 	sb.WriteString(fmt.Sprintf("type %sInterface interface {\n", modelName))
-	sb.WriteString(fmt.Sprintf("\tCreate(%s *models.%s) error\n", varName, modelName))
-	sb.WriteString(fmt.Sprintf("\tFindByID(id string) (*models.%s, error)\n", modelName))
-	sb.WriteString(fmt.Sprintf("\tFindAll(page, pageSize int) ([]models.%s, int64, error)\n", modelName))
-	sb.WriteString(fmt.Sprintf("\tUpdate(%s *models.%s) error\n", varName, modelName))
-	sb.WriteString(fmt.Sprintf("\tDelete(id string) error\n"))
+	sb.WriteString(fmt.Sprintf("\tCreate(%s) error\n", g.ctxParams(fmt.Sprintf("%s *models.%s", varName, modelName))))
+	sb.WriteString(fmt.Sprintf("\tFindByID(%s) (*models.%s, error)\n", g.ctxParams(g.keyParams()+", scopes ...func(*gorm.DB) *gorm.DB"), modelName))
+	sb.WriteString(fmt.Sprintf("\tFindAll(%s) %s\n", g.ctxParams(g.listParams()+", scopes ...func(*gorm.DB) *gorm.DB"), g.listResults()))
+	sb.WriteString(fmt.Sprintf("\tUpdate(%s) error\n", g.ctxParams(fmt.Sprintf("%s *models.%s, fields ...string", varName, modelName))))
+	sb.WriteString(fmt.Sprintf("\tDelete(%s) error\n", g.ctxParams(g.deleteParams())))
 	
 	// Ajouter des méthodes de recherche personnalisées basées sur les colonnes
 	for _, col := range g.Schema.Columns {
-		if col.Unique && col.Name != "id" {
-			fieldName := toPascalCase(col.Name)
-			sb.WriteString(fmt.Sprintf("\tFindBy%s(%s %s) (*models.%s, error)\n", 
+		if col.Unique && !g.Schema.IsPrimaryKey(col.Name) {
+			fieldName := inflection.Pascal(col.Name)
+			sb.WriteString(fmt.Sprintf("\tFindBy%s(%s) (*models.%s, error)\n", 
 				fieldName, 
-				col.Name, 
-				col.GetGoType(), 
+				g.ctxParams(col.Name+" "+col.GetGoType()+", scopes ...func(*gorm.DB) *gorm.DB"), 
 				modelName))
 		}
 	}
 	
+	for _, rel := range g.Schema.Relations {
+		if rel.Type == "morph_to" {
+			sb.WriteString(fmt.Sprintf("\t%s\n", g.morphFinderSignature(rel)))
+		}
+	}
+	if g.Schema.Tree {
+		g.writeTreeRepositoryInterface(&sb)
+	}
+	if g.Schema.SoftDeletes {
+		g.writeSoftDeleteRepositoryInterface(&sb)
+	}
+	g.writeScopeRepositoryInterface(&sb)
+	if g.search() {
+		g.writeSearchRepositoryInterface(&sb)
+	}
+	g.writeNestedRepositoryInterface(&sb)
+	if g.contextual() {
+		sb.WriteString(fmt.Sprintf("\tWithContext(ctx context.Context) %sInterface\n", modelName))
+	}
+	if g.Schema.Audit {
+		g.writeAuditRepositoryInterface(&sb)
+	}
+	
 	sb.WriteString("}\n\n")
 
 	// Structure du repository
@@ -65,36 +121,39 @@ func (g *Generator) generateRepositoryContent() string {
 	sb.WriteString("}\n\n")
 
 	// Constructeur
-	sb.WriteString(fmt.Sprintf("// New%s crée une nouvelle instance du repository\n", repoName))
-	sb.WriteString(fmt.Sprintf("func New%s() %sInterface {\n", repoName, modelName))
+	sb.WriteString(fmt.Sprintf("// New%s crée une nouvelle instance du repository sur la connexion db\n", repoName))
+	sb.WriteString(fmt.Sprintf("func New%s(db *gorm.DB) %sInterface {\n", repoName, modelName))
 	sb.WriteString(fmt.Sprintf("\treturn &%s{\n", repoName))
-	sb.WriteString("\t\tdb: config.GetDB(),\n")
+	if g.Schema.TenantScoped {
+		sb.WriteString("\t\tdb: tenant.Register(db),\n")
+	} else {
+		sb.WriteString("\t\tdb: db,\n")
+	}
 	sb.WriteString("\t}\n")
 	sb.WriteString("}\n\n")
 
 	// Méthode Create
 	sb.WriteString(fmt.Sprintf("// Create crée un nouveau %s\n", varName))
-	sb.WriteString(fmt.Sprintf("func (r *%s) Create(%s *models.%s) error {\n", repoName, varName, modelName))
-	sb.WriteString(fmt.Sprintf("\treturn r.db.Create(%s).Error\n", varName))
+	sb.WriteString(fmt.Sprintf("func (r *%s) Create(%s) error {\n", repoName, g.ctxParams(fmt.Sprintf("%s *models.%s", varName, modelName))))
+	if g.Schema.Tree {
+		g.writeTreeParentCheck(&sb)
+	}
+	if g.Schema.Audit {
+		g.writeAuditCreate(&sb)
+	} else {
+		sb.WriteString(fmt.Sprintf("\treturn %s.Create(%s).Error\n", g.repoDB(), varName))
+	}
 	sb.WriteString("}\n\n")
 
 	// Méthode FindByID
-	sb.WriteString(fmt.Sprintf("// FindByID trouve un %s par son ID\n", varName))
-	sb.WriteString(fmt.Sprintf("func (r *%s) FindByID(id string) (*models.%s, error) {\n", repoName, modelName))
+	sb.WriteString(fmt.Sprintf("// FindByID trouve un %s par sa clé primaire\n", varName))
+	sb.WriteString(fmt.Sprintf("func (r *%s) FindByID(%s) (*models.%s, error) {\n", repoName, g.ctxParams(g.keyParams()+", scopes ...func(*gorm.DB) *gorm.DB"), modelName))
 	sb.WriteString(fmt.Sprintf("\tvar %s models.%s\n", varName, modelName))
 	
-	// Ajouter les préchargements des relations
-	preloads := []string{}
-	for _, rel := range g.Schema.Relations {
-		preloads = append(preloads, rel.Model)
-	}
-	
-	query := "r.db"
-	for _, preload := range preloads {
-		query += fmt.Sprintf(".Preload(\"%s\")", preload)
-	}
+	// Les relations ne sont chargées que par les scopes (include.Preload)
+	query := g.repoDB() + ".Scopes(scopes...)"
 	
-	sb.WriteString(fmt.Sprintf("\terr := %s.First(&%s, \"id = ?\", id).Error\n", query, varName))
+	sb.WriteString(fmt.Sprintf("\terr := %s.Where(\"%s\", %s).First(&%s).Error\n", query, g.keyWhere(), g.keyArgs(), varName))
 	sb.WriteString("\tif err != nil {\n")
 	sb.WriteString("\t\tif errors.Is(err, gorm.ErrRecordNotFound) {\n")
 	sb.WriteString("\t\t\treturn nil, errors.New(\"enregistrement non trouvé\")\n")
@@ -105,63 +164,77 @@ func (g *Generator) generateRepositoryContent() string {
 	sb.WriteString("}\n\n")
 
 	// Méthode FindAll
-	sb.WriteString(fmt.Sprintf("// FindAll récupère tous les %ss avec pagination\n", varName))
-	sb.WriteString(fmt.Sprintf("func (r *%s) FindAll(page, pageSize int) ([]models.%s, int64, error) {\n", repoName, modelName))
-	sb.WriteString(fmt.Sprintf("\tvar %ss []models.%s\n", varName, modelName))
-	sb.WriteString("\tvar total int64\n\n")
-	sb.WriteString(fmt.Sprintf("\t// Compter le total\n"))
-	sb.WriteString(fmt.Sprintf("\tif err := r.db.Model(&models.%s{}).Count(&total).Error; err != nil {\n", modelName))
-	sb.WriteString("\t\treturn nil, 0, err\n")
-	sb.WriteString("\t}\n\n")
-	sb.WriteString("\t// Calculer l'offset\n")
-	sb.WriteString("\toffset := (page - 1) * pageSize\n\n")
-	sb.WriteString("\t// Récupérer les données avec pagination\n")
-	
-	query = "r.db"
-	for _, preload := range preloads {
-		query += fmt.Sprintf(".Preload(\"%s\")", preload)
+	if g.Schema.CursorPagination() {
+		sb.WriteString(fmt.Sprintf("// FindAll récupère une page de %s à partir d'un curseur (\"\" pour la première page),\n", pluralName))
+		sb.WriteString("// restreinte par les scopes éventuels\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("// FindAll récupère les %s avec pagination, restreints par les scopes éventuels\n", pluralName))
+	}
+	sb.WriteString(fmt.Sprintf("func (r *%s) FindAll(%s) %s {\n", repoName, g.ctxParams(g.listParams()+", scopes ...func(*gorm.DB) *gorm.DB"), g.listResults()))
+	if g.Schema.CursorPagination() {
+		g.writeCursorFindAll(&sb)
+		sb.WriteString("}\n\n")
+	} else {
+		g.writeOffsetFindAll(&sb)
+		sb.WriteString("}\n\n")
+	}
+	if g.search() {
+		g.writeSearchRepository(&sb)
 	}
-	
-	sb.WriteString(fmt.Sprintf("\terr := %s.\n", query))
-	sb.WriteString("\t\tOffset(offset).\n")
-	sb.WriteString("\t\tLimit(pageSize).\n")
-	sb.WriteString(fmt.Sprintf("\t\tFind(&%ss).Error\n\n", varName))
-	sb.WriteString("\tif err != nil {\n")
-	sb.WriteString("\t\treturn nil, 0, err\n")
-	sb.WriteString("\t}\n\n")
-	sb.WriteString(fmt.Sprintf("\treturn %ss, total, nil\n", varName))
-	sb.WriteString("}\n\n")
 
-	// Méthode Update
-	sb.WriteString(fmt.Sprintf("// Update met à jour un %s\n", varName))
-	sb.WriteString(fmt.Sprintf("func (r *%s) Update(%s *models.%s) error {\n", repoName, varName, modelName))
-	sb.WriteString(fmt.Sprintf("\treturn r.db.Save(%s).Error\n", varName))
+	// Méthode Update: seules les colonnes indiquées sont écrites
+	sb.WriteString(fmt.Sprintf("// Update met à jour les colonnes indiquées d'un %s\n", varName))
+	sb.WriteString(fmt.Sprintf("func (r *%s) Update(%s) error {\n", repoName, g.ctxParams(fmt.Sprintf("%s *models.%s, fields ...string", varName, modelName))))
+	sb.WriteString("\tif len(fields) == 0 {\n")
+	sb.WriteString("\t\treturn nil\n")
+	sb.WriteString("\t}\n")
+	if g.Schema.Tree {
+		g.writeTreeCycleCheck(&sb)
+	}
+	if g.Schema.HasColumn("updated_at") {
+		sb.WriteString("\tfields = append(fields, \"updated_at\")\n")
+	}
+	if g.Schema.Versioned {
+		g.writeVersionBump(&sb)
+	}
+	switch {
+	case g.Schema.Audit:
+		g.writeAuditUpdate(&sb)
+	case g.Schema.Versioned:
+		g.writeVersionedUpdate(&sb)
+	default:
+		sb.WriteString(fmt.Sprintf("\treturn %s.Model(%s).Select(fields).Updates(%s).Error\n", g.repoDB(), varName, varName))
+	}
 	sb.WriteString("}\n\n")
 
 	// Méthode Delete
 	sb.WriteString(fmt.Sprintf("// Delete supprime un %s\n", varName))
-	sb.WriteString(fmt.Sprintf("func (r *%s) Delete(id string) error {\n", repoName))
-	sb.WriteString(fmt.Sprintf("\treturn r.db.Delete(&models.%s{}, \"id = ?\", id).Error\n", modelName))
+	sb.WriteString(fmt.Sprintf("func (r *%s) Delete(%s) error {\n", repoName, g.ctxParams(g.deleteParams())))
+	switch {
+	case g.Schema.Audit:
+		g.writeAuditDelete(&sb)
+	case g.Schema.Events:
+		g.writeEventsDelete(&sb, g.repoDB(), "nil", g.Schema.Versioned)
+	case g.Schema.Versioned:
+		g.writeVersionedDelete(&sb, fmt.Sprintf("%s.Where(\"%s\", %s)", g.repoDB(), g.keyWhere(), g.keyArgs()), "&models."+modelName+"{}", "\t")
+		sb.WriteString("\treturn nil\n")
+	default:
+		sb.WriteString(fmt.Sprintf("\treturn %s.Where(\"%s\", %s).Delete(&models.%s{}).Error\n", g.repoDB(), g.keyWhere(), g.keyArgs(), modelName))
+	}
 	sb.WriteString("}\n\n")
 
 	// Méthodes de recherche personnalisées
 	for _, col := range g.Schema.Columns {
-		if col.Unique && col.Name != "id" {
-			fieldName := toPascalCase(col.Name)
+		if col.Unique && !g.Schema.IsPrimaryKey(col.Name) {
+			fieldName := inflection.Pascal(col.Name)
 			sb.WriteString(fmt.Sprintf("// FindBy%s trouve un %s par son %s\n", fieldName, varName, col.Name))
-			sb.WriteString(fmt.Sprintf("func (r *%s) FindBy%s(%s %s) (*models.%s, error) {\n", 
+			sb.WriteString(fmt.Sprintf("func (r *%s) FindBy%s(%s) (*models.%s, error) {\n", 
 				repoName, 
 				fieldName, 
-				col.Name, 
-				col.GetGoType(), 
+				g.ctxParams(col.Name+" "+col.GetGoType()+", scopes ...func(*gorm.DB) *gorm.DB"), 
 				modelName))
 			sb.WriteString(fmt.Sprintf("\tvar %s models.%s\n", varName, modelName))
 			
-			query = "r.db"
-			for _, preload := range preloads {
-				query += fmt.Sprintf(".Preload(\"%s\")", preload)
-			}
-			
 			sb.WriteString(fmt.Sprintf("\terr := %s.Where(\"%s = ?\", %s).First(&%s).Error\n", 
 				query, 
 				col.Name, 
@@ -178,5 +251,150 @@ func (g *Generator) generateRepositoryContent() string {
 		}
 	}
 
+	if g.Schema.Tree {
+		g.writeTreeRepository(&sb)
+	}
+
+	if g.Schema.SoftDeletes {
+		g.writeSoftDeleteRepository(&sb)
+	}
+	g.writeScopeRepository(&sb)
+	if g.contextual() {
+		sb.WriteString("// WithContext retourne un repository dont les requêtes portent ctx (utilisateur, tenant, annulation)\n")
+		sb.WriteString(fmt.Sprintf("func (r *%s) WithContext(ctx context.Context) %sInterface {\n", repoName, modelName))
+		sb.WriteString(fmt.Sprintf("\treturn &%s{db: r.db.WithContext(ctx)}\n", repoName))
+		sb.WriteString("}\n\n")
+	}
+	if g.Schema.Audit {
+		g.writeAuditRepository(&sb)
+	}
+
+	// Parents des relations belongs_to, pour les routes imbriquées
+	g.writeNestedRepository(&sb)
+
+	// Recherche par parent des relations polymorphes
+	for _, rel := range g.Schema.Relations {
+		if rel.Type != "morph_to" {
+			continue
+		}
+		typeColumn, idColumn := rel.MorphTypeColumn(), rel.MorphIDColumn()
+		sb.WriteString(fmt.Sprintf("// FindBy%s trouve les %s rattachés à un parent %s\n", inflection.Pascal(rel.Name), pluralName, rel.Name))
+		sb.WriteString(fmt.Sprintf("func (r *%s) %s {\n", repoName, g.morphFinderSignature(rel)))
+		sb.WriteString(fmt.Sprintf("\tvar %s []models.%s\n", pluralName, modelName))
+		sb.WriteString(fmt.Sprintf("\terr := %s.Where(\"%s = ? AND %s = ?\", %s, %s).Find(&%s).Error\n",
+			g.repoDB(),
+			typeColumn,
+			idColumn,
+			inflection.Camel(typeColumn),
+			inflection.Camel(idColumn),
+			pluralName))
+		sb.WriteString(fmt.Sprintf("\treturn %s, err\n", pluralName))
+		sb.WriteString("}\n\n")
+	}
+
 	return sb.String()
 }
+
+// legacyRepositories indique si les méthodes du repository gardent leurs anciennes
+// signatures, sans context.Context (repositories: legacy dans go-scaffold.yaml)
+func (g *Generator) legacyRepositories() bool {
+	return g.Config != nil && g.Config.Repositories == config.RepositoriesLegacy
+}
+
+// contextual indique si le repository expose WithContext: en mode legacy, c'est ainsi que
+// l'utilisateur de l'audit et le tenant atteignent les requêtes, y compris le préchargement
+// des relations audit ou tenant_scoped demandées par ?include=
+func (g *Generator) contextual() bool {
+	if !g.legacyRepositories() {
+		return false
+	}
+	return g.Schema.Audit || g.Schema.TenantScoped || g.includeReaches(func(schema *parser.Schema) bool {
+		return schema.Audit || schema.TenantScoped
+	})
+}
+
+// ctxParams ajoute ctx en tête des paramètres d'une méthode du repository
+func (g *Generator) ctxParams(params string) string {
+	if g.legacyRepositories() {
+		return params
+	}
+	if params == "" {
+		return "ctx context.Context"
+	}
+	return "ctx context.Context, " + params
+}
+
+// ctxArgs ajoute ctx en tête des arguments d'un appel interne au repository
+func (g *Generator) ctxArgs(args string) string {
+	if g.legacyRepositories() {
+		return args
+	}
+	if args == "" {
+		return "ctx"
+	}
+	return "ctx, " + args
+}
+
+// repoDB retourne la connexion utilisée dans une méthode du repository
+func (g *Generator) repoDB() string {
+	if g.legacyRepositories() {
+		return "r.db"
+	}
+	return "r.db.WithContext(ctx)"
+}
+
+// repoCall retourne l'appel d'une méthode du repository dans un handler, qui transmet
+// le contexte de la requête
+func (g *Generator) repoCall(method, args string) string {
+	switch {
+	case !g.legacyRepositories():
+		if args == "" {
+			return fmt.Sprintf("ctrl.repo.%s(c.Request.Context())", method)
+		}
+		return fmt.Sprintf("ctrl.repo.%s(c.Request.Context(), %s)", method, args)
+	case g.contextual():
+		return fmt.Sprintf("ctrl.repo.WithContext(c.Request.Context()).%s(%s)", method, args)
+	default:
+		return fmt.Sprintf("ctrl.repo.%s(%s)", method, args)
+	}
+}
+
+// morphFinderSignature retourne la signature de la recherche par parent d'une relation morph_to
+func (g *Generator) morphFinderSignature(rel parser.Relation) string {
+	idType := "int64"
+	for _, col := range g.Schema.Columns {
+		if col.Name == rel.MorphIDColumn() {
+			idType = col.BaseGoType()
+		}
+	}
+	return fmt.Sprintf("FindBy%s(%s) ([]models.%s, error)",
+		inflection.Pascal(rel.Name),
+		g.ctxParams(fmt.Sprintf("%s string, %s %s", inflection.Camel(rel.MorphTypeColumn()), inflection.Camel(rel.MorphIDColumn()), idType)),
+		g.Schema.Model,
+	)
+}
+
+// writeOffsetFindAll écrit le corps d'une recherche paginée par numéro de page: comptage
+// du total puis lecture de la page, restreints par les scopes
+func (g *Generator) writeOffsetFindAll(sb *strings.Builder) {
+	pluralName := g.pluralVar()
+	modelName := g.Schema.Model
+	sb.WriteString(fmt.Sprintf("\tvar %s []models.%s\n", pluralName, modelName))
+	sb.WriteString("\tvar total int64\n\n")
+	sb.WriteString(fmt.Sprintf("\t// Compter le total\n"))
+	sb.WriteString(fmt.Sprintf("\tif err := %s.Model(&models.%s{}).Scopes(scopes...).Count(&total).Error; err != nil {\n", g.repoDB(), modelName))
+	sb.WriteString("\t\treturn nil, 0, err\n")
+	sb.WriteString("\t}\n\n")
+	sb.WriteString("\t// Calculer l'offset\n")
+	sb.WriteString("\toffset := (page - 1) * pageSize\n\n")
+	sb.WriteString("\t// Récupérer les données avec pagination\n")
+
+	sb.WriteString(fmt.Sprintf("\terr := %s.Scopes(scopes...).\n", g.repoDB()))
+	sb.WriteString("\t\tOffset(offset).\n")
+	sb.WriteString("\t\tLimit(pageSize).\n")
+	sb.WriteString(fmt.Sprintf("\t\tFind(&%s).Error\n\n", pluralName))
+	sb.WriteString("\tif err != nil {\n")
+	sb.WriteString("\t\treturn nil, 0, err\n")
+	sb.WriteString("\t}\n\n")
+	sb.WriteString(fmt.Sprintf("\treturn %s, total, nil\n", pluralName))
+}
@@ -4,15 +4,17 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	"go-scaffold/internal/inflection"
 	"go-scaffold/internal/parser"
 )
 
 // GenerateRequests génère les fichiers de validation des requêtes
 func (g *Generator) GenerateRequests() error {
 	modelName := g.Schema.Model
-	filename := filepath.Join("app", "requests", toSnakeCase(modelName)+"_request.go")
+	filename := filepath.Join("app", "requests", inflection.Snake(modelName)+"_request.go")
 
 	// Créer le répertoire s'il n'existe pas
 	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
@@ -20,46 +22,60 @@ func (g *Generator) GenerateRequests() error {
 	}
 
 	content := g.generateRequestsContent()
-	return os.WriteFile(filename, []byte(content), 0644)
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		return err
+	}
+
+	// Validateur partagé par toutes les requests (règles personnalisées)
+	validatorFilename := filepath.Join("app", "requests", "validator.go")
+	return os.WriteFile(validatorFilename, []byte(requestValidatorContent), 0644)
 }
 
 func (g *Generator) generateRequestsContent() string {
 	var sb strings.Builder
 	modelName := g.Schema.Model
+	fields := g.requestColumns()
+	keys := g.keyInputColumns()
 
 	sb.WriteString("package requests\n\n")
-	sb.WriteString("import (\n")
-	
-	needsTime := false
-	for _, col := range g.Schema.Columns {
-		if strings.Contains(col.GetGoType(), "time.Time") {
-			needsTime = true
-			break
+
+	imports := []string{"encoding/json"}
+	for _, col := range keys {
+		imports = append(imports, col.BaseImports()...)
+	}
+	hasRequiredField := false
+	for _, col := range fields {
+		imports = append(imports, col.BaseImports()...)
+		if col.Nullable {
+			imports = append(imports, col.GetImports()...)
+		} else {
+			hasRequiredField = true
+		}
+		if _, ok := goLiteral(col); ok && col.IsDecimal() {
+			imports = append(imports, "github.com/shopspring/decimal")
 		}
 	}
-	
-	if needsTime {
-		sb.WriteString("\t\"time\"\n\n")
+	if hasRequiredField {
+		imports = append(imports, "fmt")
 	}
-	
-	sb.WriteString("\t\"app/models\"\n")
-	sb.WriteString(")\n\n")
+	writeImports(&sb, append(imports, "app/models")...)
 
 	// Create Request
 	sb.WriteString(fmt.Sprintf("// Create%sRequest représente les données pour créer un %s\n", 
-		modelName, toCamelCase(modelName)))
+		modelName, inflection.Camel(modelName)))
 	sb.WriteString(fmt.Sprintf("type Create%sRequest struct {\n", modelName))
 
-	for _, col := range g.Schema.Columns {
-		// Exclure les colonnes auto-générées
-		if col.Name == "id" || col.Name == "created_at" || col.Name == "updated_at" {
-			continue
-		}
-
-		fieldName := toPascalCase(col.Name)
-		goType := col.GetGoType()
+	for _, col := range append(append([]parser.Column{}, keys...), fields...) {
+		fieldName := inflection.Pascal(col.Name)
+		goType := col.BaseGoType()
 		jsonTag := col.GetJSONTag()
 
+		// Les champs nullables, booléens et numériques sont des pointeurs afin de
+		// distinguer un champ absent d'une valeur zéro légitime (false, 0)
+		if col.Nullable || col.IsZeroable() {
+			goType = "*" + goType
+		}
+
 		// Construire les tags de validation
 		validationTags := g.buildValidationTags(col, true)
 
@@ -76,39 +92,74 @@ func (g *Generator) generateRequestsContent() string {
 	// Méthode ToModel pour Create
 	sb.WriteString(fmt.Sprintf("// ToModel convertit la requête en model\n"))
 	sb.WriteString(fmt.Sprintf("func (r *Create%sRequest) ToModel() models.%s {\n", modelName, modelName))
-	sb.WriteString(fmt.Sprintf("\treturn models.%s{\n", modelName))
+	sb.WriteString(fmt.Sprintf("\tvar m models.%s\n", modelName))
+	for _, col := range keys {
+		fieldName := inflection.Pascal(col.Name)
+		if col.IsZeroable() {
+			sb.WriteString(fmt.Sprintf("\tif r.%s != nil {\n", fieldName))
+			sb.WriteString(fmt.Sprintf("\t\tm.%s = *r.%s\n", fieldName, fieldName))
+			sb.WriteString("\t}\n")
+		} else {
+			sb.WriteString(fmt.Sprintf("\tm.%s = r.%s\n", fieldName, fieldName))
+		}
+	}
+	sb.WriteString("\tr.Fill(&m)\n")
+	sb.WriteString("\treturn m\n")
+	sb.WriteString("}\n\n")
 
-	for _, col := range g.Schema.Columns {
-		if col.Name == "id" || col.Name == "created_at" || col.Name == "updated_at" {
-			continue
+	// Méthode Fill: remplacement complet (création et PUT)
+	sb.WriteString("// Fill affecte toutes les colonnes de la requête au model, hors clé primaire.\n")
+	sb.WriteString("// Un champ omis prend la valeur par défaut du schéma, ou NULL s'il est nullable.\n")
+	sb.WriteString(fmt.Sprintf("func (r *Create%sRequest) Fill(m *models.%s) {\n", modelName, modelName))
+
+	for _, col := range fields {
+		fieldName := inflection.Pascal(col.Name)
+		switch {
+		case col.Nullable && col.IsPointer():
+			sb.WriteString(fmt.Sprintf("\tm.%s = r.%s\n", fieldName, fieldName))
+		case col.Nullable:
+			sb.WriteString(fmt.Sprintf("\tif r.%s != nil {\n", fieldName))
+			sb.WriteString(fmt.Sprintf("\t\tm.%s = %s\n", fieldName, col.NullFromPtr("r."+fieldName)))
+			sb.WriteString("\t} else {\n")
+			sb.WriteString(fmt.Sprintf("\t\tm.%s = %s\n", fieldName, col.NullZero()))
+			sb.WriteString("\t}\n")
+		case col.IsZeroable():
+			sb.WriteString(fmt.Sprintf("\tif r.%s != nil {\n", fieldName))
+			sb.WriteString(fmt.Sprintf("\t\tm.%s = *r.%s\n", fieldName, fieldName))
+			if literal, ok := goLiteral(col); ok {
+				sb.WriteString("\t} else {\n")
+				sb.WriteString(fmt.Sprintf("\t\tm.%s = %s\n", fieldName, literal))
+			}
+			sb.WriteString("\t}\n")
+		default:
+			sb.WriteString(fmt.Sprintf("\tm.%s = r.%s\n", fieldName, fieldName))
 		}
-		fieldName := toPascalCase(col.Name)
-		sb.WriteString(fmt.Sprintf("\t\t%s: r.%s,\n", fieldName, fieldName))
 	}
 
-	sb.WriteString("\t}\n")
+	sb.WriteString("}\n\n")
+
+	// Méthode Fields pour Create
+	var columnNames []string
+	for _, col := range fields {
+		columnNames = append(columnNames, fmt.Sprintf("%q", col.Name))
+	}
+	sb.WriteString("// Fields retourne les colonnes affectées par Fill\n")
+	sb.WriteString(fmt.Sprintf("func (r *Create%sRequest) Fields() []string {\n", modelName))
+	sb.WriteString(fmt.Sprintf("\treturn []string{%s}\n", strings.Join(columnNames, ", ")))
 	sb.WriteString("}\n\n")
 
 	// Update Request
 	sb.WriteString(fmt.Sprintf("// Update%sRequest représente les données pour mettre à jour un %s\n", 
-		modelName, toCamelCase(modelName)))
+		modelName, inflection.Camel(modelName)))
 	sb.WriteString(fmt.Sprintf("type Update%sRequest struct {\n", modelName))
 
-	for _, col := range g.Schema.Columns {
-		// Exclure les colonnes qui ne peuvent pas être mises à jour
-		if col.Name == "id" || col.Name == "created_at" || col.Name == "updated_at" {
-			continue
-		}
+	for _, col := range fields {
+		fieldName := inflection.Pascal(col.Name)
 
-		fieldName := toPascalCase(col.Name)
-		goType := col.GetGoType()
-		
 		// Pour l'update, rendre les champs optionnels
-		if !strings.HasPrefix(goType, "*") && goType != "interface{}" {
-			goType = "*" + goType
-		}
+		goType := "*" + col.BaseGoType()
 		
-		jsonTag := "json:\"" + col.Name + ",omitempty\""
+		jsonTag := strings.Replace(col.GetJSONTag(), col.Name+"\"", col.Name+",omitempty\"", 1)
 
 		// Construire les tags de validation (plus souples pour update)
 		validationTags := g.buildValidationTags(col, false)
@@ -121,19 +172,76 @@ func (g *Generator) generateRequestsContent() string {
 		))
 	}
 
+	sb.WriteString("\n\tpresent map[string]bool\n")
+	sb.WriteString("}\n\n")
+
+	// Détection des champs présents (absent vs null explicite)
+	sb.WriteString("// UnmarshalJSON mémorise les champs présents dans le corps de la requête\n")
+	sb.WriteString(fmt.Sprintf("func (r *Update%sRequest) UnmarshalJSON(data []byte) error {\n", modelName))
+	sb.WriteString(fmt.Sprintf("\ttype alias Update%sRequest\n", modelName))
+	sb.WriteString("\tif err := json.Unmarshal(data, (*alias)(r)); err != nil {\n")
+	sb.WriteString("\t\treturn err\n")
+	sb.WriteString("\t}\n\n")
+	sb.WriteString("\tvar raw map[string]json.RawMessage\n")
+	sb.WriteString("\tif err := json.Unmarshal(data, &raw); err != nil {\n")
+	sb.WriteString("\t\treturn err\n")
+	sb.WriteString("\t}\n\n")
+	sb.WriteString("\tr.present = make(map[string]bool, len(raw))\n")
+	if hasRequiredField {
+		sb.WriteString("\tfor field, value := range raw {\n")
+	} else {
+		sb.WriteString("\tfor field := range raw {\n")
+	}
+	sb.WriteString("\t\tr.present[field] = true\n")
+	if hasRequiredField {
+		var required []string
+		for _, col := range fields {
+			if !col.Nullable {
+				required = append(required, fmt.Sprintf("%q", col.Name))
+			}
+		}
+		sb.WriteString("\t\tif string(value) != \"null\" {\n")
+		sb.WriteString("\t\t\tcontinue\n")
+		sb.WriteString("\t\t}\n")
+		sb.WriteString("\t\tswitch field {\n")
+		sb.WriteString(fmt.Sprintf("\t\tcase %s:\n", strings.Join(required, ", ")))
+		sb.WriteString("\t\t\treturn fmt.Errorf(\"le champ %s ne peut pas être null\", field)\n")
+		sb.WriteString("\t\t}\n")
+	}
+	sb.WriteString("\t}\n")
+	sb.WriteString("\treturn nil\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// Has indique si un champ était présent dans le corps de la requête (même à null)\n")
+	sb.WriteString(fmt.Sprintf("func (r *Update%sRequest) Has(field string) bool {\n", modelName))
+	sb.WriteString("\treturn r.present[field]\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// Fields retourne les colonnes présentes dans la requête, seules à être écrites\n")
+	sb.WriteString(fmt.Sprintf("func (r *Update%sRequest) Fields() []string {\n", modelName))
+	sb.WriteString("\tvar fields []string\n")
+	sb.WriteString(fmt.Sprintf("\tfor _, field := range []string{%s} {\n", strings.Join(columnNames, ", ")))
+	sb.WriteString("\t\tif r.present[field] {\n")
+	sb.WriteString("\t\t\tfields = append(fields, field)\n")
+	sb.WriteString("\t\t}\n")
+	sb.WriteString("\t}\n")
+	sb.WriteString("\treturn fields\n")
 	sb.WriteString("}\n\n")
 
 	// Méthode UpdateModel pour Update
 	sb.WriteString(fmt.Sprintf("// UpdateModel met à jour le model avec les données de la requête\n"))
 	sb.WriteString(fmt.Sprintf("func (r *Update%sRequest) UpdateModel(m *models.%s) {\n", modelName, modelName))
 
-	for _, col := range g.Schema.Columns {
-		if col.Name == "id" || col.Name == "created_at" || col.Name == "updated_at" {
-			continue
-		}
-		fieldName := toPascalCase(col.Name)
+	for _, col := range fields {
+		fieldName := inflection.Pascal(col.Name)
 		sb.WriteString(fmt.Sprintf("\tif r.%s != nil {\n", fieldName))
-		sb.WriteString(fmt.Sprintf("\t\tm.%s = *r.%s\n", fieldName, fieldName))
+		if col.Nullable {
+			sb.WriteString(fmt.Sprintf("\t\tm.%s = %s\n", fieldName, col.NullFromPtr("r."+fieldName)))
+			sb.WriteString(fmt.Sprintf("\t} else if r.Has(\"%s\") {\n", col.Name))
+			sb.WriteString(fmt.Sprintf("\t\tm.%s = %s\n", fieldName, col.NullZero()))
+		} else {
+			sb.WriteString(fmt.Sprintf("\t\tm.%s = *r.%s\n", fieldName, fieldName))
+		}
 		sb.WriteString("\t}\n")
 	}
 
@@ -142,22 +250,62 @@ func (g *Generator) generateRequestsContent() string {
 	return sb.String()
 }
 
+// requestColumns retourne les colonnes modifiables exposées dans les requests
+// (hors clé primaire, qui identifie la ressource dans l'URL)
+func (g *Generator) requestColumns() []parser.Column {
+	var columns []parser.Column
+	for _, col := range g.Schema.Columns {
+		// Exclure la clé primaire et les colonnes auto-générées
+		if g.Schema.IsPrimaryKey(col.Name) || col.Name == "created_at" || col.Name == "updated_at" || col.SoftDelete || col.Version || col.Tenant {
+			continue
+		}
+		columns = append(columns, col)
+	}
+	return columns
+}
+
+// keyInputColumns retourne les colonnes de clé primaire fournies par le client à la création
+func (g *Generator) keyInputColumns() []parser.Column {
+	var columns []parser.Column
+	for _, col := range g.Schema.PrimaryKeys() {
+		if !g.isGeneratedKey(col) {
+			columns = append(columns, col)
+		}
+	}
+	return columns
+}
+
 func (g *Generator) buildValidationTags(col parser.Column, isCreate bool) string {
 	var tags []string
 
 	// Chercher les validations personnalisées dans le schéma
 	for _, val := range g.Schema.Validations {
 		if val.Field == col.Name {
-			for rule, value := range val.Rules {
+			// Ordre stable des règles pour un code généré reproductible
+			rules := make([]string, 0, len(val.Rules))
+			for rule := range val.Rules {
+				rules = append(rules, rule)
+			}
+			sort.Strings(rules)
+			for _, rule := range rules {
+				value := val.Rules[rule]
 				switch rule {
 				case "required":
 					if isCreate && value == true {
 						tags = append(tags, "required")
 					}
 				case "min":
-					tags = append(tags, fmt.Sprintf("min=%v", value))
+					if col.IsDecimal() {
+						tags = append(tags, fmt.Sprintf("decimal_min=%v", value))
+					} else {
+						tags = append(tags, fmt.Sprintf("min=%v", value))
+					}
 				case "max":
-					tags = append(tags, fmt.Sprintf("max=%v", value))
+					if col.IsDecimal() {
+						tags = append(tags, fmt.Sprintf("decimal_max=%v", value))
+					} else {
+						tags = append(tags, fmt.Sprintf("max=%v", value))
+					}
 				case "email":
 					if value == true {
 						tags = append(tags, "email")
@@ -183,14 +331,26 @@ func (g *Generator) buildValidationTags(col parser.Column, isCreate bool) string
 
 	// Ajouter des validations par défaut basées sur le type
 	if len(tags) == 0 {
-		if !col.Nullable && isCreate && col.Name != "id" {
+		// Un champ ayant une valeur par défaut peut être omis
+		if !col.Nullable && isCreate && col.Default == nil {
 			tags = append(tags, "required")
 		}
 		if col.Size > 0 && col.Type == "string" {
 			tags = append(tags, fmt.Sprintf("max=%d", col.Size))
 		}
-		if col.Type == "email" {
-			tags = append(tags, "email")
+	}
+
+	// Les décimaux sont bornés par leur précision et leur échelle
+	if col.IsDecimal() && col.Precision > 0 {
+		tags = append(tags, fmt.Sprintf("decimal=%d:%d", col.Precision, col.Scale))
+	}
+
+	// Les types personnalisés apportent leurs propres validations
+	if def, ok := parser.LookupType(col.Type); ok && def.Validation != "" {
+		for _, tag := range strings.Split(def.Validation, ",") {
+			if !containsTag(tags, tag) {
+				tags = append(tags, tag)
+			}
 		}
 	}
 
@@ -198,5 +358,134 @@ func (g *Generator) buildValidationTags(col parser.Column, isCreate bool) string
 		return ""
 	}
 
+	// Un champ nullable, ayant une valeur par défaut, ou absent d'une mise à jour partielle,
+	// n'est validé que s'il est renseigné
+	if (col.Nullable || col.Default != nil || !isCreate) && !containsTag(tags, "required") {
+		tags = append([]string{"omitempty"}, tags...)
+	}
+
 	return fmt.Sprintf("validate:\"%s\"", strings.Join(tags, ","))
 }
+
+// containsTag indique si une règle de validation est déjà présente
+func containsTag(tags []string, tag string) bool {
+	name := strings.SplitN(tag, "=", 2)[0]
+	for _, t := range tags {
+		if strings.SplitN(t, "=", 2)[0] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// requestValidatorContent contient le validateur partagé des requests
+const requestValidatorContent = `package requests
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+)
+
+// NewValidator crée un validateur avec les règles propres au projet
+func NewValidator() *validator.Validate {
+	v := validator.New()
+	_ = v.RegisterValidation("decimal", validateDecimal)
+	_ = v.RegisterValidation("decimal_min", validateDecimalMin)
+	_ = v.RegisterValidation("decimal_max", validateDecimalMax)
+	return v
+}
+
+// decimalValue extrait la valeur décimale d'un champ
+func decimalValue(field reflect.Value) (decimal.Decimal, bool) {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return decimal.Decimal{}, false
+		}
+		field = field.Elem()
+	}
+	d, ok := field.Interface().(decimal.Decimal)
+	return d, ok
+}
+
+// validateDecimal vérifie la précision et l'échelle d'un décimal (decimal=10:2)
+func validateDecimal(fl validator.FieldLevel) bool {
+	d, ok := decimalValue(fl.Field())
+	if !ok {
+		return false
+	}
+
+	parts := strings.SplitN(fl.Param(), ":", 2)
+	precision, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return false
+	}
+	scale := 0
+	if len(parts) == 2 {
+		if scale, err = strconv.Atoi(parts[1]); err != nil {
+			return false
+		}
+	}
+
+	// Pas plus de décimales que l'échelle
+	if !d.Equal(d.Truncate(int32(scale))) {
+		return false
+	}
+
+	// Partie entière limitée à (précision - échelle) chiffres
+	return d.Abs().LessThan(decimal.New(1, int32(precision-scale)))
+}
+
+// validateDecimalMin vérifie qu'un décimal est supérieur ou égal au paramètre
+func validateDecimalMin(fl validator.FieldLevel) bool {
+	d, ok := decimalValue(fl.Field())
+	if !ok {
+		return false
+	}
+	min, err := decimal.NewFromString(fl.Param())
+	if err != nil {
+		return false
+	}
+	return d.GreaterThanOrEqual(min)
+}
+
+// validateDecimalMax vérifie qu'un décimal est inférieur ou égal au paramètre
+func validateDecimalMax(fl validator.FieldLevel) bool {
+	d, ok := decimalValue(fl.Field())
+	if !ok {
+		return false
+	}
+	max, err := decimal.NewFromString(fl.Param())
+	if err != nil {
+		return false
+	}
+	return d.LessThanOrEqual(max)
+}
+`
+
+// goLiteral retourne la valeur par défaut de la colonne sous forme de littéral Go
+func goLiteral(col parser.Column) (string, bool) {
+	if col.Default == nil {
+		return "", false
+	}
+
+	switch col.BaseGoType() {
+	case "string":
+		return fmt.Sprintf("%q", fmt.Sprintf("%v", col.Default)), true
+	case "bool":
+		if v, ok := col.Default.(bool); ok {
+			return fmt.Sprintf("%t", v), true
+		}
+	case "int", "int16", "int32", "int64", "float64":
+		switch col.Default.(type) {
+		case int, float64:
+			return fmt.Sprintf("%v", col.Default), true
+		}
+	case "decimal.Decimal":
+		return fmt.Sprintf("decimal.RequireFromString(%q)", fmt.Sprintf("%v", col.Default)), true
+	}
+	return "", false
+}
@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"testing"
+
+	"go-scaffold/internal/config"
+)
+
+// noteSchema déclare des colonnes nullables, avec et sans valeur par défaut
+const noteSchema = `
+table: notes
+model: Note
+columns:
+  - {name: id, type: bigint, primary: true, auto_increment: true}
+  - {name: title, type: string, size: 100, nullable: true}
+  - {name: rank, type: integer, default: 3, nullable: true}
+  - {name: state, type: string, default: draft}
+validations:
+  - {field: state, rules: {in: [draft, published]}}`
+
+func TestGenerateNullable(t *testing.T) {
+	tests := []struct {
+		style  string
+		model  []string
+		fill   string
+		shared string
+	}{
+		{"pointer", []string{"Title *string", "Rank *int"}, "m.Title = r.Title", ""},
+		{"sql", []string{"Title sql.NullString", "Rank sql.Null[int]"}, "m.Title = sql.NullString{String: *r.Title, Valid: true}", ""},
+		{"generic", []string{"Title types.Null[string]", "Rank types.Null[int]"}, "m.Title = types.NewNull(*r.Title)", "types/null.go"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.style, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Nullable = tt.style
+			files := generate(t, cfg, noteSchema)
+
+			contains(t, files, "app/models/note.go", tt.model...)
+			contains(t, files, "app/requests/note_request.go", "Title *string", tt.fill)
+			if tt.shared != "" {
+				contains(t, files, "app/"+tt.shared, "type Null[T any] struct")
+			}
+		})
+	}
+}
+
+// Une colonne avec valeur par défaut peut être omise à la création malgré ses règles
+func TestGenerateDefaults(t *testing.T) {
+	files := generate(t, nil, noteSchema)
+
+	contains(t, files, "app/requests/note_request.go",
+		"State string `json:\"state\" validate:\"omitempty,oneof=draft published\"`",
+	)
+	contains(t, files, "app/models/note.go", "default:draft", "default:3")
+}
+
+func TestGeneratePatch(t *testing.T) {
+	files := generate(t, nil, noteSchema)
+
+	contains(t, files, "routes/note_routes.go", `.PATCH("/:id", ctrl.Patch)`)
+	contains(t, files, "app/requests/note_request.go",
+		"type UpdateNoteRequest struct",
+		"State *string `json:\"state,omitempty\" validate:\"omitempty,oneof=draft published\"`",
+		"present map[string]bool",
+		"func (r *UpdateNoteRequest) UnmarshalJSON(data []byte) error",
+		"func (r *UpdateNoteRequest) Fields() []string",
+		`} else if r.Has("title") {`,
+	)
+	contains(t, files, "app/controllers/note_controller.go", "func (ctrl *NoteController) Patch(c *gin.Context)", "req.Fields()")
+}
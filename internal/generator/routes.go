@@ -5,12 +5,14 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"go-scaffold/internal/inflection"
 )
 
 // GenerateRoutes génère ou met à jour le fichier de routes
 func (g *Generator) GenerateRoutes() error {
 	modelName := g.Schema.Model
-	routeFilename := filepath.Join("routes", toSnakeCase(modelName)+"_routes.go")
+	routeFilename := filepath.Join("routes", inflection.Snake(modelName)+"_routes.go")
 
 	// Créer le répertoire s'il n'existe pas
 	if err := os.MkdirAll(filepath.Dir(routeFilename), 0755); err != nil {
@@ -30,34 +32,55 @@ func (g *Generator) GenerateRoutes() error {
 func (g *Generator) generateRouteContent() string {
 	var sb strings.Builder
 	modelName := g.Schema.Model
-	varName := toCamelCase(modelName)
-	resourceName := toSnakeCase(modelName) + "s"
+	varName := inflection.Camel(modelName)
+	resourceName := g.resourceName()
 
 	sb.WriteString("package routes\n\n")
 	sb.WriteString("import (\n")
-	sb.WriteString("\t\"app/controllers\"\n\n")
+	sb.WriteString("\t\"app/controllers\"\n")
+	if g.Schema.TenantScoped || g.tenantIncludes() {
+		sb.WriteString("\t\"app/tenant\"\n")
+	}
+	sb.WriteString("\n")
 	sb.WriteString("\t\"github.com/gin-gonic/gin\"\n")
 	sb.WriteString(")\n\n")
 
-	sb.WriteString(fmt.Sprintf("// Register%sRoutes enregistre les routes pour %s\n", 
+	sb.WriteString(fmt.Sprintf("// Register%sRoutes enregistre les routes pour %s, servies par ctrl\n", 
 		modelName, modelName))
-	sb.WriteString(fmt.Sprintf("func Register%sRoutes(router *gin.RouterGroup) {\n", modelName))
-	sb.WriteString(fmt.Sprintf("\tctrl := controllers.New%sController()\n\n", modelName))
+	sb.WriteString(fmt.Sprintf("func Register%sRoutes(router *gin.RouterGroup, ctrl *controllers.%sController) {\n", modelName, modelName))
 	
 	sb.WriteString(fmt.Sprintf("\t// Routes RESTful pour %s\n", varName))
-	sb.WriteString(fmt.Sprintf("\t%sGroup := router.Group(\"/%s\")\n", varName, resourceName))
+	if g.Schema.TenantScoped {
+		sb.WriteString(fmt.Sprintf("\t%sGroup := router.Group(\"/%s\", tenant.Middleware())\n", varName, resourceName))
+	} else {
+		sb.WriteString(fmt.Sprintf("\t%sGroup := router.Group(\"/%s\")\n", varName, resourceName))
+	}
 	sb.WriteString("\t{\n")
-	sb.WriteString(fmt.Sprintf("\t\t%sGroup.GET(\"\", ctrl.Index)        // GET /%s\n", 
-		varName, resourceName))
+	sb.WriteString(fmt.Sprintf("\t\t%sGroup.GET(\"\", %sctrl.Index)        // GET /%s\n", 
+		varName, g.includeMiddleware(), resourceName))
 	sb.WriteString(fmt.Sprintf("\t\t%sGroup.POST(\"\", ctrl.Store)       // POST /%s\n", 
 		varName, resourceName))
-	sb.WriteString(fmt.Sprintf("\t\t%sGroup.GET(\"/:id\", ctrl.Show)     // GET /%s/:id\n", 
-		varName, resourceName))
-	sb.WriteString(fmt.Sprintf("\t\t%sGroup.PUT(\"/:id\", ctrl.Update)   // PUT /%s/:id\n", 
-		varName, resourceName))
-	sb.WriteString(fmt.Sprintf("\t\t%sGroup.DELETE(\"/:id\", ctrl.Delete) // DELETE /%s/:id\n", 
-		varName, resourceName))
+	keyRoute := g.keyRoute()
+	sb.WriteString(fmt.Sprintf("\t\t%sGroup.GET(\"%s\", %sctrl.Show)     // GET /%s%s\n", 
+		varName, keyRoute, g.includeMiddleware(), resourceName, keyRoute))
+	sb.WriteString(fmt.Sprintf("\t\t%sGroup.PUT(\"%s\", ctrl.Update)   // PUT /%s%s\n", 
+		varName, keyRoute, resourceName, keyRoute))
+	sb.WriteString(fmt.Sprintf("\t\t%sGroup.PATCH(\"%s\", ctrl.Patch)  // PATCH /%s%s\n", 
+		varName, keyRoute, resourceName, keyRoute))
+	sb.WriteString(fmt.Sprintf("\t\t%sGroup.DELETE(\"%s\", ctrl.Delete) // DELETE /%s%s\n", 
+		varName, keyRoute, resourceName, keyRoute))
+	if g.Schema.Tree {
+		g.writeTreeRoutes(&sb)
+	}
+	if g.Schema.SoftDeletes {
+		g.writeSoftDeleteRoutes(&sb)
+	}
+	if g.Schema.Audit {
+		g.writeAuditRoutes(&sb)
+	}
+	g.writeNestedRoutes(&sb)
 	sb.WriteString("\t}\n")
+	g.writeNestedParentRoutes(&sb)
 	sb.WriteString("}\n")
 
 	return sb.String()
@@ -76,11 +99,26 @@ func (g *Generator) updateMainRoutesFile() error {
 
 	contentStr := string(content)
 
+	// Construire le conteneur de dépendances dans les fichiers antérieurs à app/container.go
+	if !strings.Contains(contentStr, "app.NewContainer(") {
+		contentStr, err = addContainer(contentStr)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Vérifier si la route est déjà enregistrée
-	registerCall := fmt.Sprintf("Register%sRoutes(api)", modelName)
+	registerCall := fmt.Sprintf("Register%sRoutes(api, container.%sController)", modelName, modelName)
 	if strings.Contains(contentStr, registerCall) {
 		// La route est déjà enregistrée
-		return nil
+		return os.WriteFile(mainRoutesFile, []byte(contentStr), 0644)
+	}
+
+	// Ancien enregistrement, sans contrôleur
+	legacyCall := fmt.Sprintf("Register%sRoutes(api)", modelName)
+	if strings.Contains(contentStr, legacyCall) {
+		contentStr = strings.Replace(contentStr, legacyCall, registerCall, 1)
+		return os.WriteFile(mainRoutesFile, []byte(contentStr), 0644)
 	}
 
 	// Trouver où insérer le nouvel appel
@@ -94,7 +132,7 @@ func (g *Generator) updateMainRoutesFile() error {
 	lastNewline := strings.LastIndex(contentStr[:insertPos], "\n")
 	
 	// Insérer le nouvel appel
-	newCall := fmt.Sprintf("\n\t\tRegister%sRoutes(api)", modelName)
+	newCall := "\n\t\t" + registerCall
 	newContent := contentStr[:lastNewline] + newCall + contentStr[lastNewline:]
 
 	return os.WriteFile(mainRoutesFile, []byte(newContent), 0644)
@@ -107,10 +145,16 @@ func (g *Generator) createMainRoutesFile() error {
 	content := `package routes
 
 import (
+	"app"
+	"config"
+
 	"github.com/gin-gonic/gin"
 )
 
 func RegisterRoutes(router *gin.Engine) {
+	// Dépendances de l'application (connexion, repositories, contrôleurs)
+	container := app.NewContainer(config.GetDB())
+
 	// Routes de l'API
 	api := router.Group("/api")
 	{
@@ -123,10 +167,28 @@ func RegisterRoutes(router *gin.Engine) {
 		})
 
 		// Routes générées
-		Register` + modelName + `Routes(api)
+		Register` + modelName + `Routes(api, container.` + modelName + `Controller)
 	}
 }
 `
 
 	return os.WriteFile(mainRoutesFile, []byte(content), 0644)
 }
+
+// addContainer ajoute à un fichier routes.go la construction du conteneur de dépendances
+// dont les enregistrements de routes tirent leurs contrôleurs
+func addContainer(content string) (string, error) {
+	signature := "func RegisterRoutes(router *gin.Engine) {\n"
+	if !strings.Contains(content, signature) || !strings.Contains(content, "import (\n") {
+		return "", fmt.Errorf("impossible d'ajouter le conteneur de dépendances dans routes.go")
+	}
+
+	imports := "\t\"app\"\n"
+	if !strings.Contains(content, "config\"\n") {
+		imports += "\t\"config\"\n"
+	}
+	content = strings.Replace(content, "import (\n", "import (\n"+imports+"\n", 1)
+	return strings.Replace(content, signature, signature+
+		"\t// Dépendances de l'application (connexion, repositories, contrôleurs)\n"+
+		"\tcontainer := app.NewContainer(config.GetDB())\n\n", 1), nil
+}
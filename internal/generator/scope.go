@@ -0,0 +1,183 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"go-scaffold/internal/inflection"
+	"go-scaffold/internal/parser"
+)
+
+// Les scopes d'un schéma deviennent des fonctions de scope GORM dans le model
+// (db.Scopes(models.ScopeArticlePublished)) et des méthodes paginées du repository.
+// Les scopes sans paramètre sont aussi sélectionnables sur la liste via ?scope=.
+
+// scopeFunc retourne le nom de la fonction de scope générée dans le package models
+func (g *Generator) scopeFunc(scope parser.Scope) string {
+	return "Scope" + g.Schema.Model + inflection.Pascal(scope.Name)
+}
+
+// scopeMap retourne le nom de la table des scopes sélectionnables par ?scope=
+func (g *Generator) scopeMap() string {
+	return g.Schema.Model + "Scopes"
+}
+
+// listScopes retourne les scopes sans paramètre, utilisables sur la liste
+func (g *Generator) listScopes() []parser.Scope {
+	var scopes []parser.Scope
+	for _, scope := range g.Schema.Scopes {
+		if !scope.HasParameters() {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// scopeParams retourne la liste des paramètres typés d'un scope (authorID int64, ...)
+func scopeParams(scope parser.Scope) string {
+	var params []string
+	for _, param := range scope.Parameters {
+		params = append(params, inflection.Camel(param.Name)+" "+param.GoType())
+	}
+	return strings.Join(params, ", ")
+}
+
+// scopeArgs retourne les arguments d'appel d'un scope (authorID, ...)
+func scopeArgs(scope parser.Scope) string {
+	var args []string
+	for _, param := range scope.Parameters {
+		args = append(args, inflection.Camel(param.Name))
+	}
+	return strings.Join(args, ", ")
+}
+
+// scopeImports retourne les imports nécessaires aux paramètres des scopes
+func (g *Generator) scopeImports() []string {
+	var imports []string
+	for _, scope := range g.Schema.Scopes {
+		for _, param := range scope.Parameters {
+			imports = append(imports, param.Imports()...)
+		}
+	}
+	return imports
+}
+
+// scopeQuery retourne la chaîne d'appels Where d'un scope
+func scopeQuery(scope parser.Scope) string {
+	query := "db"
+	for _, cond := range scope.Conditions {
+		switch {
+		case cond.IsUnary():
+			query += fmt.Sprintf(".Where(%q)", cond.Where())
+		case cond.Param != "":
+			query += fmt.Sprintf(".Where(%q, %s)", cond.Where(), inflection.Camel(cond.Param))
+		default:
+			query += fmt.Sprintf(".Where(%q, %s)", cond.Where(), scopeValue(cond.Value))
+		}
+	}
+	return query
+}
+
+// scopeValue retourne le littéral Go d'une valeur de condition
+func scopeValue(value interface{}) string {
+	if list, ok := value.([]interface{}); ok {
+		values := make([]string, len(list))
+		for i, v := range list {
+			values[i] = fmt.Sprintf("%#v", v)
+		}
+		return "[]interface{}{" + strings.Join(values, ", ") + "}"
+	}
+	return fmt.Sprintf("%#v", value)
+}
+
+// writeModelScopes écrit les fonctions de scope GORM et la table des scopes de liste
+func (g *Generator) writeModelScopes(sb *strings.Builder) {
+	pluralName := g.pluralVar()
+	for _, scope := range g.Schema.Scopes {
+		name := g.scopeFunc(scope)
+		sb.WriteString(fmt.Sprintf("// %s restreint une requête aux %s du scope %s\n", name, pluralName, scope.Name))
+		if !scope.HasParameters() {
+			sb.WriteString(fmt.Sprintf("func %s(db *gorm.DB) *gorm.DB {\n", name))
+			sb.WriteString(fmt.Sprintf("\treturn %s\n", scopeQuery(scope)))
+			sb.WriteString("}\n\n")
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("func %s(%s) func(*gorm.DB) *gorm.DB {\n", name, scopeParams(scope)))
+		sb.WriteString("\treturn func(db *gorm.DB) *gorm.DB {\n")
+		sb.WriteString(fmt.Sprintf("\t\treturn %s\n", scopeQuery(scope)))
+		sb.WriteString("\t}\n")
+		sb.WriteString("}\n\n")
+	}
+
+	scopes := g.listScopes()
+	if len(scopes) == 0 {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("// %s associe les scopes sans paramètre à leur nom, pour le paramètre ?scope= de la liste\n", g.scopeMap()))
+	sb.WriteString(fmt.Sprintf("var %s = map[string]func(*gorm.DB) *gorm.DB{\n", g.scopeMap()))
+	for _, scope := range scopes {
+		sb.WriteString(fmt.Sprintf("\t%q: %s,\n", scope.Name, g.scopeFunc(scope)))
+	}
+	sb.WriteString("}\n\n")
+}
+
+// scopeMethodSignature retourne la signature de la méthode de repository d'un scope
+func (g *Generator) scopeMethodSignature(scope parser.Scope) string {
+	params := g.listParams()
+	if scope.HasParameters() {
+		params = scopeParams(scope) + ", " + params
+	}
+	return fmt.Sprintf("%s(%s) %s", inflection.Pascal(scope.Name), g.ctxParams(params), g.listResults())
+}
+
+// writeScopeRepositoryInterface écrit les méthodes des scopes dans l'interface du repository
+func (g *Generator) writeScopeRepositoryInterface(sb *strings.Builder) {
+	for _, scope := range g.Schema.Scopes {
+		sb.WriteString(fmt.Sprintf("\t%s\n", g.scopeMethodSignature(scope)))
+	}
+}
+
+// writeScopeRepository écrit une méthode paginée par scope, déléguée à FindAll
+func (g *Generator) writeScopeRepository(sb *strings.Builder) {
+	repoName := g.Schema.Model + "Repository"
+	pluralName := g.pluralVar()
+	for _, scope := range g.Schema.Scopes {
+		method := inflection.Pascal(scope.Name)
+		fn := "models." + g.scopeFunc(scope)
+		if scope.HasParameters() {
+			fn += "(" + scopeArgs(scope) + ")"
+		}
+		sb.WriteString(fmt.Sprintf("// %s récupère les %s du scope %s avec pagination\n", method, pluralName, scope.Name))
+		sb.WriteString(fmt.Sprintf("func (r *%s) %s {\n", repoName, g.scopeMethodSignature(scope)))
+		sb.WriteString(fmt.Sprintf("\treturn r.FindAll(%s)\n", g.ctxArgs(g.listArgs()+", "+fn)))
+		sb.WriteString("}\n\n")
+	}
+}
+
+// writeScopeDocParam écrit la documentation du paramètre ?scope= de la liste
+func (g *Generator) writeScopeDocParam(sb *strings.Builder) {
+	var names []string
+	for _, scope := range g.listScopes() {
+		names = append(names, scope.Name)
+	}
+	sb.WriteString(fmt.Sprintf("// @Param scope query []string false \"Scopes à appliquer (%s)\"\n", strings.Join(names, ", ")))
+}
+
+// writeScopeSelection écrit, dans Index, la résolution des scopes demandés par ?scope=;
+// declare déclare la liste des scopes, sinon reçue en paramètre
+func (g *Generator) writeScopeSelection(sb *strings.Builder, declare bool) {
+	sb.WriteString("\t// Scopes demandés (?scope=published&scope=...)\n")
+	if declare {
+		sb.WriteString("\tvar scopes []func(*gorm.DB) *gorm.DB\n")
+	}
+	sb.WriteString("\tfor _, name := range c.QueryArray(\"scope\") {\n")
+	sb.WriteString(fmt.Sprintf("\t\tscope, ok := models.%s[name]\n", g.scopeMap()))
+	sb.WriteString("\t\tif !ok {\n")
+	sb.WriteString("\t\t\tc.JSON(http.StatusBadRequest, gin.H{\n")
+	sb.WriteString("\t\t\t\t\"error\": \"Scope inconnu: \" + name,\n")
+	sb.WriteString("\t\t\t})\n")
+	sb.WriteString("\t\t\treturn\n")
+	sb.WriteString("\t\t}\n")
+	sb.WriteString("\t\tscopes = append(scopes, scope)\n")
+	sb.WriteString("\t}\n\n")
+}
@@ -0,0 +1,26 @@
+package generator
+
+import "testing"
+
+func TestGenerateScopes(t *testing.T) {
+	files := generate(t, nil, `
+table: posts
+model: Post
+columns:
+  - {name: id, type: bigint, primary: true, auto_increment: true}
+  - {name: status, type: string, size: 20}
+  - {name: views, type: integer}
+scopes:
+  - {name: published, conditions: [{column: status, value: published}]}
+  - {name: popular, parameters: [{name: min, type: integer}], conditions: [{column: views, operator: ">=", param: min}]}`)
+
+	contains(t, files, "app/models/post.go",
+		"func ScopePostPublished(db *gorm.DB) *gorm.DB {",
+		`db.Where("status = ?", "published")`,
+		"func ScopePostPopular(min int) func(*gorm.DB) *gorm.DB {",
+		`"views >= ?", min`,
+		`"published": ScopePostPublished,`,
+	)
+	contains(t, files, "app/repositories/post_repository.go", ") Published(", ") Popular(")
+	contains(t, files, "app/controllers/post_controller.go", `c.QueryArray("scope")`, "models.PostScopes[name]")
+}
@@ -0,0 +1,237 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"go-scaffold/internal/inflection"
+	"go-scaffold/internal/parser"
+)
+
+// Les colonnes searchable: true alimentent une recherche plein texte propre au dialecte:
+// un index GIN sur to_tsvector en PostgreSQL, un index FULLTEXT en MySQL et une table
+// virtuelle FTS5 synchronisée par triggers en SQLite. Le model expose le filtre et le
+// classement par pertinence sous forme de scopes, utilisés par la méthode Search du
+// repository et par le paramètre ?q= de la liste.
+
+// searchLanguage est la configuration de recherche PostgreSQL: simple ne retire ni les
+// mots vides ni les terminaisons, et convient donc à toutes les langues
+const searchLanguage = "simple"
+
+// search indique si le schéma a des colonnes de recherche plein texte
+func (g *Generator) search() bool {
+	return len(g.Schema.SearchableColumns()) > 0
+}
+
+// searchIndex retourne le nom de l'index de recherche (et de la table FTS5 en SQLite)
+func (g *Generator) searchIndex() string {
+	if g.Config.Dialect == parser.DialectSQLite {
+		return g.Schema.Table + "_fts"
+	}
+	return "idx_" + g.Schema.Table + "_search"
+}
+
+// searchScope retourne le nom du scope qui restreint une requête aux résultats de la recherche
+func (g *Generator) searchScope() string {
+	return "Scope" + g.Schema.Model + "Search"
+}
+
+// searchRankScope retourne le nom du scope qui trie les résultats par pertinence
+func (g *Generator) searchRankScope() string {
+	return "Scope" + g.Schema.Model + "SearchRank"
+}
+
+// searchMatch retourne la fonction qui convertit la saisie en requête FTS5
+func (g *Generator) searchMatch() string {
+	return inflection.Camel(g.Schema.Model) + "Match"
+}
+
+// searchColumns retourne les colonnes searchable protégées selon le dialecte
+func (g *Generator) searchColumns(quote bool) []string {
+	var columns []string
+	for _, col := range g.Schema.SearchableColumns() {
+		if quote {
+			columns = append(columns, quoteIdent(g.Config.Dialect, col.Name))
+		} else {
+			columns = append(columns, col.Name)
+		}
+	}
+	return columns
+}
+
+// searchVector retourne le document PostgreSQL indexé: la concaténation des colonnes searchable
+func (g *Generator) searchVector(quote bool) string {
+	var parts []string
+	for _, col := range g.searchColumns(quote) {
+		parts = append(parts, fmt.Sprintf("coalesce(%s, '')", col))
+	}
+	return fmt.Sprintf("to_tsvector('%s', %s)", searchLanguage, strings.Join(parts, " || ' ' || "))
+}
+
+// searchCondition retourne la condition SQL de la recherche, paramétrée par la saisie
+func (g *Generator) searchCondition() string {
+	switch g.Config.Dialect {
+	case parser.DialectMySQL:
+		return fmt.Sprintf("MATCH(%s) AGAINST(? IN NATURAL LANGUAGE MODE)", strings.Join(g.searchColumns(false), ", "))
+	case parser.DialectSQLite:
+		return fmt.Sprintf("%s IN (SELECT rowid FROM %s WHERE %s MATCH ?)", g.Schema.PrimaryKeys()[0].Name, g.searchIndex(), g.searchIndex())
+	}
+	return fmt.Sprintf("%s @@ websearch_to_tsquery('%s', ?)", g.searchVector(false), searchLanguage)
+}
+
+// searchRank retourne l'expression de tri par pertinence, paramétrée par la saisie
+func (g *Generator) searchRank() string {
+	switch g.Config.Dialect {
+	case parser.DialectMySQL:
+		return g.searchCondition() + " DESC"
+	case parser.DialectSQLite:
+		// bm25 est négatif: les meilleurs résultats ont le rang le plus petit
+		return fmt.Sprintf("(SELECT rank FROM %s WHERE %s MATCH ? AND rowid = %s.%s)",
+			g.searchIndex(), g.searchIndex(), g.Schema.Table, g.Schema.PrimaryKeys()[0].Name)
+	}
+	return fmt.Sprintf("ts_rank(%s, websearch_to_tsquery('%s', ?)) DESC", g.searchVector(false), searchLanguage)
+}
+
+// searchImports retourne les imports du model nécessaires à la recherche
+func (g *Generator) searchImports() []string {
+	imports := []string{"gorm.io/gorm/clause"}
+	if g.Config.Dialect == parser.DialectSQLite {
+		imports = append(imports, "strings")
+	}
+	return imports
+}
+
+// writeSearchMigration écrit l'index plein texte des colonnes searchable
+func (g *Generator) writeSearchMigration(sb *strings.Builder) {
+	dialect := g.Config.Dialect
+	table := g.Schema.Table
+	columns := g.searchColumns(true)
+
+	sb.WriteString(fmt.Sprintf("\n-- Recherche plein texte (%s)\n", strings.Join(g.searchColumns(false), ", ")))
+	switch dialect {
+	case parser.DialectMySQL:
+		g.writeCreateIndex(sb, "FULLTEXT ", g.searchIndex(), "("+strings.Join(columns, ", ")+")")
+	case parser.DialectSQLite:
+		fts := quoteIdent(dialect, g.searchIndex())
+		key := g.Schema.PrimaryKeys()[0].Name
+		var newValues, oldValues []string
+		for _, col := range g.searchColumns(false) {
+			newValues = append(newValues, "new."+quoteIdent(dialect, col))
+			oldValues = append(oldValues, "old."+quoteIdent(dialect, col))
+		}
+		list := strings.Join(columns, ", ")
+		insert := fmt.Sprintf("    INSERT INTO %s(rowid, %s) VALUES (new.%s, %s);\n",
+			fts, list, quoteIdent(dialect, key), strings.Join(newValues, ", "))
+		remove := fmt.Sprintf("    INSERT INTO %s(%s, rowid, %s) VALUES ('delete', old.%s, %s);\n",
+			fts, fts, list, quoteIdent(dialect, key), strings.Join(oldValues, ", "))
+
+		sb.WriteString(fmt.Sprintf("CREATE VIRTUAL TABLE IF NOT EXISTS %s USING fts5(%s, content=%s, content_rowid=%s);\n",
+			fts, list, sqlLiteral(table), sqlLiteral(key)))
+		sb.WriteString(fmt.Sprintf("INSERT INTO %s(%s) VALUES ('rebuild');\n", fts, fts))
+		sb.WriteString(fmt.Sprintf("\nCREATE TRIGGER IF NOT EXISTS %s AFTER INSERT ON %s BEGIN\n", quoteIdent(dialect, g.searchIndex()+"_insert"), quoteIdent(dialect, table)))
+		sb.WriteString(insert)
+		sb.WriteString("END;\n")
+		sb.WriteString(fmt.Sprintf("\nCREATE TRIGGER IF NOT EXISTS %s AFTER DELETE ON %s BEGIN\n", quoteIdent(dialect, g.searchIndex()+"_delete"), quoteIdent(dialect, table)))
+		sb.WriteString(remove)
+		sb.WriteString("END;\n")
+		sb.WriteString(fmt.Sprintf("\nCREATE TRIGGER IF NOT EXISTS %s AFTER UPDATE ON %s BEGIN\n", quoteIdent(dialect, g.searchIndex()+"_update"), quoteIdent(dialect, table)))
+		sb.WriteString(remove)
+		sb.WriteString(insert)
+		sb.WriteString("END;\n")
+	default:
+		g.writeCreateIndex(sb, "", g.searchIndex(), "USING GIN ("+g.searchVector(true)+")")
+	}
+}
+
+// writeModelSearch écrit les scopes de recherche plein texte du model
+func (g *Generator) writeModelSearch(sb *strings.Builder) {
+	pluralName := g.pluralVar()
+	value := "q"
+	if g.Config.Dialect == parser.DialectSQLite {
+		value = g.searchMatch() + "(q)"
+	}
+
+	sb.WriteString(fmt.Sprintf("// %s restreint une requête aux %s correspondant à la recherche q\n", g.searchScope(), pluralName))
+	sb.WriteString(fmt.Sprintf("func %s(q string) func(*gorm.DB) *gorm.DB {\n", g.searchScope()))
+	sb.WriteString("\treturn func(db *gorm.DB) *gorm.DB {\n")
+	sb.WriteString(fmt.Sprintf("\t\treturn db.Where(%q, %s)\n", g.searchCondition(), value))
+	sb.WriteString("\t}\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString(fmt.Sprintf("// %s trie les %s par pertinence pour la recherche q; le tri est\n", g.searchRankScope(), pluralName))
+	sb.WriteString("// ignoré pendant le comptage du total\n")
+	sb.WriteString(fmt.Sprintf("func %s(q string) func(*gorm.DB) *gorm.DB {\n", g.searchRankScope()))
+	sb.WriteString("\treturn func(db *gorm.DB) *gorm.DB {\n")
+	sb.WriteString("\t\tif _, counting := db.Statement.Dest.(*int64); counting {\n")
+	sb.WriteString("\t\t\treturn db\n")
+	sb.WriteString("\t\t}\n")
+	sb.WriteString("\t\treturn db.Order(clause.OrderBy{Expression: clause.Expr{\n")
+	sb.WriteString(fmt.Sprintf("\t\t\tSQL:                %q,\n", g.searchRank()))
+	sb.WriteString(fmt.Sprintf("\t\t\tVars:               []interface{}{%s},\n", value))
+	sb.WriteString("\t\t\tWithoutParentheses: true,\n")
+	sb.WriteString("\t\t}})\n")
+	sb.WriteString("\t}\n")
+	sb.WriteString("}\n\n")
+
+	if g.Config.Dialect == parser.DialectSQLite {
+		sb.WriteString(fmt.Sprintf("// %s convertit la saisie en requête FTS5: chaque mot est cité, ce qui\n", g.searchMatch()))
+		sb.WriteString("// neutralise la syntaxe FTS5 (opérateurs, guillemets, préfixes)\n")
+		sb.WriteString(fmt.Sprintf("func %s(q string) string {\n", g.searchMatch()))
+		sb.WriteString("\twords := strings.Fields(q)\n")
+		sb.WriteString("\tfor i, word := range words {\n")
+		sb.WriteString("\t\twords[i] = `\"` + strings.ReplaceAll(word, `\"`, `\"\"`) + `\"`\n")
+		sb.WriteString("\t}\n")
+		sb.WriteString("\treturn strings.Join(words, \" \")\n")
+		sb.WriteString("}\n\n")
+	}
+}
+
+// searchMethodSignature retourne la signature de la méthode Search du repository
+func (g *Generator) searchMethodSignature() string {
+	return fmt.Sprintf("Search(%s) ([]models.%s, int64, error)",
+		g.ctxParams("q string, page, pageSize int, scopes ...func(*gorm.DB) *gorm.DB"), g.Schema.Model)
+}
+
+// writeSearchRepositoryInterface écrit la méthode Search dans l'interface du repository
+func (g *Generator) writeSearchRepositoryInterface(sb *strings.Builder) {
+	sb.WriteString(fmt.Sprintf("\t%s\n", g.searchMethodSignature()))
+}
+
+// writeSearchRepository écrit la méthode Search: une page de résultats classés par
+// pertinence, quel que soit le mode de pagination de la liste
+func (g *Generator) writeSearchRepository(sb *strings.Builder) {
+	repoName := g.Schema.Model + "Repository"
+	sb.WriteString(fmt.Sprintf("// Search recherche les %s correspondant à q, les plus pertinents d'abord, avec\n", g.pluralVar()))
+	sb.WriteString("// pagination et restreints par les scopes éventuels\n")
+	sb.WriteString(fmt.Sprintf("func (r *%s) %s {\n", repoName, g.searchMethodSignature()))
+	sb.WriteString(fmt.Sprintf("\tscopes = append(scopes, models.%s(q), models.%s(q))\n\n", g.searchScope(), g.searchRankScope()))
+	g.writeOffsetFindAll(sb)
+	sb.WriteString("}\n\n")
+}
+
+// writeSearchDocParam documente le paramètre q de la liste
+func (g *Generator) writeSearchDocParam(sb *strings.Builder) {
+	sb.WriteString(fmt.Sprintf("// @Param q query string false \"Recherche plein texte (%s)\"\n", strings.Join(g.searchColumns(false), ", ")))
+}
+
+// writeSearchSelection ajoute la recherche ?q= aux scopes d'une liste paginée par curseur:
+// les résultats sont filtrés mais gardent l'ordre du curseur
+func (g *Generator) writeSearchSelection(sb *strings.Builder) {
+	sb.WriteString("\t// Recherche plein texte (?q=), sans classement par pertinence pour préserver le curseur\n")
+	sb.WriteString("\tif q := c.Query(\"q\"); q != \"\" {\n")
+	sb.WriteString(fmt.Sprintf("\t\tscopes = append(scopes, models.%s(q))\n", g.searchScope()))
+	sb.WriteString("\t}\n\n")
+}
+
+// writeSearchIndex récupère la page de la liste: Search si ?q= est fourni, FindAll sinon
+func (g *Generator) writeSearchIndex(sb *strings.Builder, args string) {
+	pluralName := g.pluralVar()
+	sb.WriteString("\t// Recherche plein texte (?q=): les résultats sont classés par pertinence\n")
+	sb.WriteString(fmt.Sprintf("\tvar %s []models.%s\n", pluralName, g.Schema.Model))
+	sb.WriteString("\tvar total int64\n")
+	sb.WriteString("\tif q := c.Query(\"q\"); q != \"\" {\n")
+	sb.WriteString(fmt.Sprintf("\t\t%s, total, err = %s\n", pluralName, g.repoCall("Search", "q, "+args)))
+	sb.WriteString("\t} else {\n")
+	sb.WriteString(fmt.Sprintf("\t\t%s, total, err = %s\n", pluralName, g.repoCall("FindAll", args)))
+	sb.WriteString("\t}\n")
+}
@@ -0,0 +1,220 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"go-scaffold/internal/inflection"
+)
+
+// Les schémas soft_deletes utilisent gorm.DeletedAt: Delete renseigne deleted_at et
+// les requêtes ignorent les enregistrements supprimés. Le repository expose en plus
+// la corbeille (FindTrashed), la restauration et la suppression définitive.
+
+// writeSoftDeleteRepositoryInterface écrit les méthodes de corbeille dans l'interface du repository
+func (g *Generator) writeSoftDeleteRepositoryInterface(sb *strings.Builder) {
+	modelName := g.Schema.Model
+	sb.WriteString(fmt.Sprintf("\tFindTrashed(%s) ([]models.%s, int64, error)\n", g.ctxParams("page, pageSize int"), modelName))
+	sb.WriteString(fmt.Sprintf("\tRestore(%s) error\n", g.ctxParams(g.keyParams())))
+	sb.WriteString(fmt.Sprintf("\tForceDelete(%s) error\n", g.ctxParams(g.keyParams())))
+}
+
+// softDeleteMissingError retourne le nom de l'erreur renvoyée par Restore et ForceDelete
+// lorsque l'enregistrement n'existe pas
+func (g *Generator) softDeleteMissingError() string {
+	return "Err" + g.Schema.Model + "NotFound"
+}
+
+// writeSoftDeleteRepositoryError écrit l'erreur d'enregistrement introuvable du repository
+func (g *Generator) writeSoftDeleteRepositoryError(sb *strings.Builder) {
+	varName := inflection.Camel(g.Schema.Model)
+	sb.WriteString(fmt.Sprintf("// %s est renvoyée lorsque le %s à restaurer ou supprimer n'existe pas\n", g.softDeleteMissingError(), varName))
+	sb.WriteString(fmt.Sprintf("var %s = errors.New(\"enregistrement non trouvé\")\n\n", g.softDeleteMissingError()))
+}
+
+// writeSoftDeleteRepository écrit les méthodes FindTrashed, Restore et ForceDelete
+func (g *Generator) writeSoftDeleteRepository(sb *strings.Builder) {
+	modelName := g.Schema.Model
+	repoName := modelName + "Repository"
+	varName := inflection.Camel(modelName)
+	pluralName := g.pluralVar()
+
+	// FindTrashed
+	sb.WriteString(fmt.Sprintf("// FindTrashed récupère les %s supprimés avec pagination\n", pluralName))
+	sb.WriteString(fmt.Sprintf("func (r *%s) FindTrashed(%s) ([]models.%s, int64, error) {\n", repoName, g.ctxParams("page, pageSize int"), modelName))
+	sb.WriteString(fmt.Sprintf("\tvar %s []models.%s\n", pluralName, modelName))
+	sb.WriteString("\tvar total int64\n\n")
+	sb.WriteString(fmt.Sprintf("\tif err := %s.Unscoped().Model(&models.%s{}).Where(\"deleted_at IS NOT NULL\").Count(&total).Error; err != nil {\n", g.repoDB(), modelName))
+	sb.WriteString("\t\treturn nil, 0, err\n")
+	sb.WriteString("\t}\n\n")
+	sb.WriteString("\toffset := (page - 1) * pageSize\n")
+	sb.WriteString(fmt.Sprintf("\terr := %s.Unscoped().\n", g.repoDB()))
+	sb.WriteString("\t\tWhere(\"deleted_at IS NOT NULL\").\n")
+	sb.WriteString("\t\tOrder(\"deleted_at DESC\").\n")
+	sb.WriteString("\t\tOffset(offset).\n")
+	sb.WriteString("\t\tLimit(pageSize).\n")
+	sb.WriteString(fmt.Sprintf("\t\tFind(&%s).Error\n\n", pluralName))
+	sb.WriteString("\tif err != nil {\n")
+	sb.WriteString("\t\treturn nil, 0, err\n")
+	sb.WriteString("\t}\n\n")
+	sb.WriteString(fmt.Sprintf("\treturn %s, total, nil\n", pluralName))
+	sb.WriteString("}\n\n")
+
+	// Restore
+	sb.WriteString(fmt.Sprintf("// Restore restaure un %s supprimé\n", varName))
+	sb.WriteString(fmt.Sprintf("func (r *%s) Restore(%s) error {\n", repoName, g.ctxParams(g.keyParams())))
+	switch {
+	case g.Schema.Audit:
+		g.writeAuditRestore(sb)
+	case g.Schema.Events:
+		g.writeEventsRestore(sb)
+	default:
+		sb.WriteString(fmt.Sprintf("\tresult := %s.Unscoped().Model(&models.%s{}).\n", g.repoDB(), modelName))
+		sb.WriteString(fmt.Sprintf("\t\tWhere(\"%s AND deleted_at IS NOT NULL\", %s).\n", g.keyWhere(), g.keyArgs()))
+		sb.WriteString("\t\tUpdate(\"deleted_at\", nil)\n")
+		sb.WriteString("\tif result.Error != nil {\n")
+		sb.WriteString("\t\treturn result.Error\n")
+		sb.WriteString("\t}\n")
+		sb.WriteString("\tif result.RowsAffected == 0 {\n")
+		sb.WriteString(fmt.Sprintf("\t\treturn %s\n", g.softDeleteMissingError()))
+		sb.WriteString("\t}\n")
+		sb.WriteString("\treturn nil\n")
+	}
+	sb.WriteString("}\n\n")
+
+	// ForceDelete
+	sb.WriteString(fmt.Sprintf("// ForceDelete supprime définitivement un %s, qu'il soit dans la corbeille ou non\n", varName))
+	sb.WriteString(fmt.Sprintf("func (r *%s) ForceDelete(%s) error {\n", repoName, g.ctxParams(g.keyParams())))
+	switch {
+	case g.Schema.Audit:
+		g.writeAuditForceDelete(sb)
+	case g.Schema.Events:
+		g.writeEventsDelete(sb, g.repoDB()+".Unscoped()", g.softDeleteMissingError(), false)
+	default:
+		sb.WriteString(fmt.Sprintf("\tresult := %s.Unscoped().Where(\"%s\", %s).Delete(&models.%s{})\n", g.repoDB(), g.keyWhere(), g.keyArgs(), modelName))
+		sb.WriteString("\tif result.Error != nil {\n")
+		sb.WriteString("\t\treturn result.Error\n")
+		sb.WriteString("\t}\n")
+		sb.WriteString("\tif result.RowsAffected == 0 {\n")
+		sb.WriteString(fmt.Sprintf("\t\treturn %s\n", g.softDeleteMissingError()))
+		sb.WriteString("\t}\n")
+		sb.WriteString("\treturn nil\n")
+	}
+	sb.WriteString("}\n\n")
+}
+
+// writeSoftDeleteHandlers écrit les endpoints de corbeille, de restauration et de suppression définitive
+func (g *Generator) writeSoftDeleteHandlers(sb *strings.Builder) {
+	modelName := g.Schema.Model
+	controllerName := modelName + "Controller"
+	varName := inflection.Camel(modelName)
+	pluralName := g.pluralVar()
+	resource := g.resourceName()
+
+	// Trashed
+	sb.WriteString(fmt.Sprintf("// Trashed récupère la liste des %s supprimés\n", pluralName))
+	sb.WriteString(fmt.Sprintf("// @Summary Corbeille des %s\n", pluralName))
+	sb.WriteString("// @Description Récupère les " + pluralName + " supprimés avec pagination\n")
+	sb.WriteString("// @Tags " + modelName + "\n")
+	sb.WriteString("// @Accept json\n")
+	sb.WriteString("// @Produce json\n")
+	sb.WriteString("// @Param page query int false \"Numéro de page\" default(1)\n")
+	sb.WriteString("// @Param page_size query int false \"Taille de page\" default(10)\n")
+	sb.WriteString("// @Success 200 {object} map[string]interface{}\n")
+	sb.WriteString("// @Router /" + resource + "/trashed [get]\n")
+	sb.WriteString(fmt.Sprintf("func (ctrl *%s) Trashed(c *gin.Context) {\n", controllerName))
+	sb.WriteString("\tpage, _ := strconv.Atoi(c.DefaultQuery(\"page\", \"1\"))\n")
+	sb.WriteString("\tpageSize, _ := strconv.Atoi(c.DefaultQuery(\"page_size\", \"10\"))\n\n")
+	sb.WriteString("\tif page < 1 {\n")
+	sb.WriteString("\t\tpage = 1\n")
+	sb.WriteString("\t}\n")
+	sb.WriteString("\tif pageSize < 1 || pageSize > 100 {\n")
+	sb.WriteString("\t\tpageSize = 10\n")
+	sb.WriteString("\t}\n\n")
+	sb.WriteString(fmt.Sprintf("\t%s, total, err := %s\n", pluralName, g.repoCall("FindTrashed", "page, pageSize")))
+	sb.WriteString("\tif err != nil {\n")
+	sb.WriteString("\t\tc.JSON(http.StatusInternalServerError, gin.H{\n")
+	sb.WriteString("\t\t\t\"error\": \"Erreur lors de la récupération des données\",\n")
+	sb.WriteString("\t\t})\n")
+	sb.WriteString("\t\treturn\n")
+	sb.WriteString("\t}\n\n")
+	sb.WriteString("\tc.JSON(http.StatusOK, gin.H{\n")
+	sb.WriteString(fmt.Sprintf("\t\t\"data\": %s,\n", pluralName))
+	sb.WriteString("\t\t\"pagination\": gin.H{\n")
+	sb.WriteString("\t\t\t\"page\": page,\n")
+	sb.WriteString("\t\t\t\"page_size\": pageSize,\n")
+	sb.WriteString("\t\t\t\"total\": total,\n")
+	sb.WriteString("\t\t\t\"total_pages\": (total + int64(pageSize) - 1) / int64(pageSize),\n")
+	sb.WriteString("\t\t},\n")
+	sb.WriteString("\t})\n")
+	sb.WriteString("}\n\n")
+
+	// Restore
+	sb.WriteString(fmt.Sprintf("// Restore restaure un %s supprimé\n", varName))
+	sb.WriteString(fmt.Sprintf("// @Summary Restaurer un %s\n", varName))
+	sb.WriteString("// @Description Restaure un " + varName + " présent dans la corbeille\n")
+	sb.WriteString("// @Tags " + modelName + "\n")
+	sb.WriteString("// @Accept json\n")
+	sb.WriteString("// @Produce json\n")
+	g.writeKeyDocParams(sb)
+	sb.WriteString(fmt.Sprintf("// @Success 200 {object} models.%s\n", modelName))
+	sb.WriteString("// @Router /" + resource + g.keyDocRoute() + "/restore [post]\n")
+	sb.WriteString(fmt.Sprintf("func (ctrl *%s) Restore(c *gin.Context) {\n", controllerName))
+	g.writeKeyParsing(sb)
+	sb.WriteString(fmt.Sprintf("\tif err := %s; err != nil {\n", g.repoCall("Restore", g.keyArgs())))
+	sb.WriteString("\t\tc.JSON(http.StatusNotFound, gin.H{\n")
+	sb.WriteString("\t\t\t\"error\": \"Enregistrement supprimé non trouvé\",\n")
+	sb.WriteString("\t\t})\n")
+	sb.WriteString("\t\treturn\n")
+	sb.WriteString("\t}\n\n")
+	sb.WriteString(fmt.Sprintf("\t%s, err := %s\n", varName, g.repoCall("FindByID", g.keyArgs())))
+	sb.WriteString("\tif err != nil {\n")
+	sb.WriteString("\t\tc.JSON(http.StatusInternalServerError, gin.H{\n")
+	sb.WriteString("\t\t\t\"error\": \"Erreur lors de la récupération des données\",\n")
+	sb.WriteString("\t\t})\n")
+	sb.WriteString("\t\treturn\n")
+	sb.WriteString("\t}\n\n")
+	sb.WriteString(fmt.Sprintf("\tc.JSON(http.StatusOK, %s)\n", varName))
+	sb.WriteString("}\n\n")
+
+	// ForceDelete
+	sb.WriteString(fmt.Sprintf("// ForceDelete supprime définitivement un %s\n", varName))
+	sb.WriteString(fmt.Sprintf("// @Summary Supprimer définitivement un %s\n", varName))
+	sb.WriteString("// @Description Supprime définitivement un " + varName + ", y compris depuis la corbeille\n")
+	sb.WriteString("// @Tags " + modelName + "\n")
+	sb.WriteString("// @Accept json\n")
+	sb.WriteString("// @Produce json\n")
+	g.writeKeyDocParams(sb)
+	sb.WriteString("// @Success 204\n")
+	sb.WriteString("// @Failure 404 {object} map[string]interface{}\n")
+	sb.WriteString("// @Router /" + resource + g.keyDocRoute() + "/force [delete]\n")
+	sb.WriteString(fmt.Sprintf("func (ctrl *%s) ForceDelete(c *gin.Context) {\n", controllerName))
+	g.writeKeyParsing(sb)
+	sb.WriteString(fmt.Sprintf("\tif err := %s; err != nil {\n", g.repoCall("ForceDelete", g.keyArgs())))
+	sb.WriteString(fmt.Sprintf("\t\tif errors.Is(err, repositories.%s) {\n", g.softDeleteMissingError()))
+	sb.WriteString("\t\t\tc.JSON(http.StatusNotFound, gin.H{\n")
+	sb.WriteString("\t\t\t\t\"error\": \"Enregistrement non trouvé\",\n")
+	sb.WriteString("\t\t\t})\n")
+	sb.WriteString("\t\t\treturn\n")
+	sb.WriteString("\t\t}\n")
+	sb.WriteString("\t\tc.JSON(http.StatusInternalServerError, gin.H{\n")
+	sb.WriteString("\t\t\t\"error\": \"Erreur lors de la suppression\",\n")
+	sb.WriteString("\t\t})\n")
+	sb.WriteString("\t\treturn\n")
+	sb.WriteString("\t}\n\n")
+	sb.WriteString("\tc.Status(http.StatusNoContent)\n")
+	sb.WriteString("}\n\n")
+}
+
+// writeSoftDeleteRoutes écrit les routes de corbeille
+func (g *Generator) writeSoftDeleteRoutes(sb *strings.Builder) {
+	varName := inflection.Camel(g.Schema.Model)
+	resource := g.resourceName()
+	keyRoute := g.keyRoute()
+	sb.WriteString(fmt.Sprintf("\t\t%sGroup.GET(\"/trashed\", ctrl.Trashed) // GET /%s/trashed\n",
+		varName, resource))
+	sb.WriteString(fmt.Sprintf("\t\t%sGroup.POST(\"%s/restore\", ctrl.Restore) // POST /%s%s/restore\n",
+		varName, keyRoute, resource, keyRoute))
+	sb.WriteString(fmt.Sprintf("\t\t%sGroup.DELETE(\"%s/force\", ctrl.ForceDelete) // DELETE /%s%s/force\n",
+		varName, keyRoute, resource, keyRoute))
+}
@@ -0,0 +1,202 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go-scaffold/internal/parser"
+)
+
+// Les schémas tenant_scoped: true ne voient que les enregistrements du tenant de la
+// requête. Le package app/tenant installe des callbacks GORM qui ajoutent la condition
+// sur la colonne de tenant à chaque lecture, mise à jour et suppression, et la renseignent
+// à chaque insertion. Le tenant est résolu par un middleware sur les routes de la ressource;
+// un enregistrement d'un autre tenant est introuvable (404).
+
+// GenerateTenant génère le package app/tenant
+func (g *Generator) GenerateTenant() error {
+	filename := filepath.Join("app", "tenant", "tenant.go")
+
+	// Créer le répertoire s'il n'existe pas
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+
+	content := strings.Replace(tenantPackageContent, "{{column}}", parser.TenantColumn(), 1)
+	return os.WriteFile(filename, []byte(content), 0644)
+}
+
+// writeTenantMarker écrit la méthode qui désigne le model aux callbacks de app/tenant
+func (g *Generator) writeTenantMarker(sb *strings.Builder) {
+	sb.WriteString("// TenantScoped restreint les requêtes sur ce model au tenant du contexte\n")
+	sb.WriteString(fmt.Sprintf("func (%s) TenantScoped() {}\n\n", g.Schema.Model))
+}
+
+// writeTenantExistence écrit, dans un handler, la vérification que l'enregistrement
+// existe et appartient au tenant de la requête (404 sinon)
+func (g *Generator) writeTenantExistence(sb *strings.Builder) {
+	sb.WriteString(fmt.Sprintf("\tif _, err := %s; err != nil {\n", g.repoCall("FindByID", g.keyArgs())))
+	sb.WriteString("\t\tc.JSON(http.StatusNotFound, gin.H{\n")
+	sb.WriteString("\t\t\t\"error\": \"Enregistrement non trouvé\",\n")
+	sb.WriteString("\t\t})\n")
+	sb.WriteString("\t\treturn\n")
+	sb.WriteString("\t}\n\n")
+}
+
+// tenantPackageContent contient le package de multi-tenant des projets générés
+const tenantPackageContent = `package tenant
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Column est la colonne de tenant des tables tenant_scoped (tenant_column de go-scaffold.yaml)
+const Column = "{{column}}"
+
+// ErrMissing est renvoyée lorsqu'une requête sur un model tenant_scoped n'a pas de tenant
+var ErrMissing = errors.New("tenant absent du contexte de la requête")
+
+// Scoped est implémentée par les models tenant_scoped
+type Scoped interface {
+	TenantScoped()
+}
+
+// Resolver extrait le tenant d'une requête HTTP; remplacez-le au démarrage pour
+// l'extraire d'un jeton ou d'un sous-domaine
+var Resolver = FromHeader("X-Tenant-ID")
+
+type tenantKey struct{}
+
+// WithTenant retourne un contexte portant le tenant
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// From retourne le tenant porté par le contexte
+func From(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	id, ok := ctx.Value(tenantKey{}).(string)
+	return id, ok && id != ""
+}
+
+// FromHeader résout le tenant à partir d'un en-tête HTTP
+func FromHeader(name string) func(c *gin.Context) string {
+	return func(c *gin.Context) string {
+		return c.GetHeader(name)
+	}
+}
+
+// Middleware place le tenant résolu par Resolver dans le contexte de la requête,
+// et rejette la requête (400) si aucun tenant n'est fourni
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := Resolver(c)
+		if id == "" {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": "Tenant manquant",
+			})
+			return
+		}
+		c.Request = c.Request.WithContext(WithTenant(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// Register installe, une seule fois par connexion, les callbacks qui restreignent les
+// requêtes des models Scoped au tenant du contexte et le renseignent à l'insertion
+func Register(db *gorm.DB) *gorm.DB {
+	if db.Callback().Query().Get("tenant:query") != nil {
+		return db
+	}
+	db.Callback().Create().Before("gorm:create").Register("tenant:create", assign)
+	db.Callback().Query().Before("gorm:query").Register("tenant:query", restrict)
+	db.Callback().Update().Before("gorm:update").Register("tenant:update", restrict)
+	db.Callback().Delete().Before("gorm:delete").Register("tenant:delete", restrict)
+	db.Callback().Row().Before("gorm:row").Register("tenant:row", restrict)
+	return db
+}
+
+// scoped indique si la requête porte sur un model Scoped
+func scoped(db *gorm.DB) bool {
+	if db.Statement.Schema == nil {
+		return false
+	}
+	_, ok := reflect.New(db.Statement.Schema.ModelType).Interface().(Scoped)
+	return ok
+}
+
+// restrict ajoute la condition sur la colonne de tenant
+func restrict(db *gorm.DB) {
+	if !scoped(db) {
+		return
+	}
+	typed, err := value(db.Statement.Context, db.Statement.Schema)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: Column}, Value: typed},
+	}})
+}
+
+// Value retourne le tenant du contexte de db, dans le type Go de la colonne de tenant de
+// model, pour les requêtes SQL brutes que les callbacks ne restreignent pas
+func Value(db *gorm.DB, model interface{}) (interface{}, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+	return value(db.Statement.Context, stmt.Schema)
+}
+
+// value convertit le tenant de ctx dans le type Go de la colonne de tenant de sch
+func value(ctx context.Context, sch *schema.Schema) (interface{}, error) {
+	field := sch.LookUpField(Column)
+	id, ok := From(ctx)
+	if field == nil || !ok {
+		return nil, ErrMissing
+	}
+	model := reflect.New(sch.ModelType).Elem()
+	if err := field.Set(ctx, model, id); err != nil {
+		return nil, err
+	}
+	typed, _ := field.ValueOf(ctx, model)
+	return typed, nil
+}
+
+// assign renseigne la colonne de tenant des enregistrements insérés
+func assign(db *gorm.DB) {
+	if !scoped(db) {
+		return
+	}
+	field := db.Statement.Schema.LookUpField(Column)
+	id, ok := From(db.Statement.Context)
+	if field == nil || !ok {
+		db.AddError(ErrMissing)
+		return
+	}
+
+	switch rv := db.Statement.ReflectValue; rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			db.AddError(field.Set(db.Statement.Context, reflect.Indirect(rv.Index(i)), id))
+		}
+	case reflect.Struct:
+		db.AddError(field.Set(db.Statement.Context, rv, id))
+	}
+}
+`
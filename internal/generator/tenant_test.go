@@ -0,0 +1,36 @@
+package generator
+
+import (
+	"testing"
+
+	"go-scaffold/internal/config"
+)
+
+func TestGenerateTenant(t *testing.T) {
+	cfg := config.Default()
+	cfg.TenantColumn = "account_id"
+	files := generate(t, cfg, `
+table: categories
+model: Category
+tree: true
+tenant_scoped: true
+columns:
+  - {name: id, type: bigint, primary: true, auto_increment: true}
+  - {name: name, type: string}
+  - {name: parent_id, type: bigint, nullable: true}
+relations:
+  - {type: belongs_to, model: Category, name: parent, foreign_key: parent_id}`)
+
+	contains(t, files, "app/models/category.go",
+		"AccountID string `json:\"account_id\" gorm:\"not null;",
+		"func (Category) TenantScoped() {}",
+	)
+	contains(t, files, "app/repositories/category_repository.go",
+		"tenant.Register(db)",
+		"AND t.account_id = ?",
+		"ErrCategoryParent",
+	)
+	contains(t, files, "routes/category_routes.go", "tenant.Middleware()")
+	contains(t, files, "app/tenant/tenant.go", "package tenant", "func WithTenant(")
+	excludes(t, files, "app/requests/category_request.go", "AccountID")
+}
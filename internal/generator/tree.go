@@ -0,0 +1,298 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"go-scaffold/internal/inflection"
+	"go-scaffold/internal/parser"
+)
+
+// Les schémas tree: true représentent une hiérarchie (catégories, organigrammes)
+// par une clé étrangère vers la même table. Le repository parcourt l'arbre avec
+// des CTE récursives (WITH RECURSIVE, supporté par PostgreSQL, MySQL 8 et SQLite).
+
+// treeKeys retourne la colonne parente et la clé primaire d'un schéma tree
+func (g *Generator) treeKeys() (string, string) {
+	rel, _ := g.Schema.TreeRelation()
+	return rel.ForeignKey, g.Schema.PrimaryKeys()[0].Name
+}
+
+// treeParentColumn retourne la colonne parente d'un schéma tree
+func (g *Generator) treeParentColumn() parser.Column {
+	parentColumn, _ := g.treeKeys()
+	for _, col := range g.Schema.Columns {
+		if col.Name == parentColumn {
+			return col
+		}
+	}
+	return parser.Column{Name: parentColumn}
+}
+
+// treeTenant retourne la condition sur la colonne de tenant des CTE d'un schéma tree
+// tenant_scoped (les requêtes brutes échappent aux callbacks de app/tenant)
+func (g *Generator) treeTenant(alias string) string {
+	if !g.Schema.TenantScoped {
+		return ""
+	}
+	return fmt.Sprintf(" AND %s%s = ?", alias, parser.TenantColumn())
+}
+
+// treeTenantArgs retourne les arguments de tenant des n branches d'une CTE
+func (g *Generator) treeTenantArgs(n int) string {
+	if !g.Schema.TenantScoped {
+		return ""
+	}
+	return strings.Repeat(", tenantID", n)
+}
+
+// writeTreeTenantValue écrit la lecture du tenant passé aux CTE; ret est la liste
+// de retours qui précède l'erreur
+func (g *Generator) writeTreeTenantValue(sb *strings.Builder, ret string) {
+	if !g.Schema.TenantScoped {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("\ttenantID, err := tenant.Value(%s, &models.%s{})\n", g.repoDB(), g.Schema.Model))
+	sb.WriteString("\tif err != nil {\n")
+	sb.WriteString(fmt.Sprintf("\t\treturn %serr\n", ret))
+	sb.WriteString("\t}\n")
+}
+
+// treeAssign retourne l'opérateur d'affectation de l'erreur de la CTE
+func (g *Generator) treeAssign() string {
+	if g.Schema.TenantScoped {
+		return "="
+	}
+	return ":="
+}
+
+// treeCycleError retourne le nom de l'erreur renvoyée lorsqu'une mise à jour créerait un cycle
+func (g *Generator) treeCycleError() string {
+	return "Err" + g.Schema.Model + "Cycle"
+}
+
+// writeTreeRepositoryInterface écrit les méthodes de parcours dans l'interface du repository
+func (g *Generator) writeTreeRepositoryInterface(sb *strings.Builder) {
+	modelName := g.Schema.Model
+	sb.WriteString(fmt.Sprintf("\tAncestors(%s) ([]models.%s, error)\n", g.ctxParams(g.keyParams()), modelName))
+	sb.WriteString(fmt.Sprintf("\tDescendants(%s) ([]models.%s, error)\n", g.ctxParams(g.keyParams()), modelName))
+}
+
+// writeTreeRepositoryError écrit l'erreur de cycle du repository
+func (g *Generator) writeTreeRepositoryError(sb *strings.Builder) {
+	varName := inflection.Camel(g.Schema.Model)
+	sb.WriteString(fmt.Sprintf("// %s est renvoyée lorsqu'un %s deviendrait son propre ancêtre\n", g.treeCycleError(), varName))
+	sb.WriteString(fmt.Sprintf("var %s = errors.New(\"le parent choisi est le %s lui-même ou l'un de ses descendants\")\n\n", g.treeCycleError(), varName))
+	if g.Schema.TenantScoped {
+		sb.WriteString(fmt.Sprintf("// %s est renvoyée lorsque le parent d'un %s n'existe pas pour le tenant\n", g.treeParentError(), varName))
+		sb.WriteString(fmt.Sprintf("var %s = errors.New(\"le parent choisi est introuvable\")\n\n", g.treeParentError()))
+	}
+}
+
+// treeParentError retourne le nom de l'erreur renvoyée lorsque le parent est introuvable
+func (g *Generator) treeParentError() string {
+	return "Err" + g.Schema.Model + "Parent"
+}
+
+// writeTreeParentCheck écrit, dans Create, la vérification que le parent appartient au tenant
+func (g *Generator) writeTreeParentCheck(sb *strings.Builder) {
+	if !g.Schema.TenantScoped {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("\tif err := r.checkParent(%s); err != nil {\n", g.ctxArgs(inflection.Camel(g.Schema.Model))))
+	sb.WriteString("\t\treturn err\n")
+	sb.WriteString("\t}\n")
+}
+
+// writeTreeErrors écrit, dans Store et Update, les réponses 422 des erreurs de l'arbre
+func (g *Generator) writeTreeErrors(sb *strings.Builder, cycle bool) {
+	var names []string
+	if cycle {
+		names = append(names, g.treeCycleError())
+	}
+	if g.Schema.TenantScoped {
+		names = append(names, g.treeParentError())
+	}
+	for _, name := range names {
+		sb.WriteString(fmt.Sprintf("\t\tif errors.Is(err, repositories.%s) {\n", name))
+		sb.WriteString("\t\t\tc.JSON(http.StatusUnprocessableEntity, gin.H{\n")
+		sb.WriteString("\t\t\t\t\"error\": err.Error(),\n")
+		sb.WriteString("\t\t\t})\n")
+		sb.WriteString("\t\t\treturn\n")
+		sb.WriteString("\t\t}\n")
+	}
+}
+
+// writeTreeCycleCheck écrit, dans Update, la vérification du nouveau parent
+func (g *Generator) writeTreeCycleCheck(sb *strings.Builder) {
+	parentColumn, _ := g.treeKeys()
+	varName := inflection.Camel(g.Schema.Model)
+	sb.WriteString("\tfor _, field := range fields {\n")
+	sb.WriteString(fmt.Sprintf("\t\tif field == \"%s\" {\n", parentColumn))
+	if g.Schema.TenantScoped {
+		sb.WriteString(fmt.Sprintf("\t\t\tif err := r.checkParent(%s); err != nil {\n", g.ctxArgs(varName)))
+		sb.WriteString("\t\t\t\treturn err\n")
+		sb.WriteString("\t\t\t}\n")
+	}
+	sb.WriteString(fmt.Sprintf("\t\t\tif err := r.checkCycle(%s); err != nil {\n", g.ctxArgs(varName)))
+	sb.WriteString("\t\t\t\treturn err\n")
+	sb.WriteString("\t\t\t}\n")
+	sb.WriteString("\t\t}\n")
+	sb.WriteString("\t}\n")
+}
+
+// writeTreeRepository écrit les méthodes Ancestors, Descendants et checkCycle
+// (et checkParent pour un schéma tenant_scoped)
+func (g *Generator) writeTreeRepository(sb *strings.Builder) {
+	modelName := g.Schema.Model
+	repoName := modelName + "Repository"
+	varName := inflection.Camel(modelName)
+	pluralName := g.pluralVar()
+	table := g.Schema.Table
+	parentColumn, keyColumn := g.treeKeys()
+
+	// Ancestors: du parent direct jusqu'à la racine
+	sb.WriteString(fmt.Sprintf("// Ancestors retourne les ancêtres d'un %s, du parent direct jusqu'à la racine\n", varName))
+	sb.WriteString(fmt.Sprintf("func (r *%s) Ancestors(%s) ([]models.%s, error) {\n", repoName, g.ctxParams(g.keyParams()), modelName))
+	g.writeTreeTenantValue(sb, "nil, ")
+	sb.WriteString(fmt.Sprintf("\tvar %s []models.%s\n", pluralName, modelName))
+	sb.WriteString(fmt.Sprintf("\terr %s %s.Raw(`WITH RECURSIVE ancestors AS (\n", g.treeAssign(), g.repoDB()))
+	sb.WriteString(fmt.Sprintf("\t\tSELECT t.*, 1 AS depth FROM %s t\n", table))
+	sb.WriteString(fmt.Sprintf("\t\tWHERE t.%s = (SELECT %s FROM %s WHERE %s = ?%s)%s\n",
+		keyColumn, parentColumn, table, keyColumn, g.treeTenant(""), g.treeTenant("t.")))
+	sb.WriteString("\t\tUNION ALL\n")
+	sb.WriteString(fmt.Sprintf("\t\tSELECT t.*, a.depth + 1 FROM %s t JOIN ancestors a ON t.%s = a.%s%s\n",
+		table, keyColumn, parentColumn, g.treeTenant("t.")))
+	sb.WriteString(fmt.Sprintf("\t)\n\tSELECT * FROM ancestors ORDER BY depth`, %s%s).Scan(&%s).Error\n",
+		g.keyArgs(), g.treeTenantArgs(3), pluralName))
+	sb.WriteString(fmt.Sprintf("\treturn %s, err\n", pluralName))
+	sb.WriteString("}\n\n")
+
+	// Descendants: tout le sous-arbre, niveau par niveau
+	sb.WriteString(fmt.Sprintf("// Descendants retourne le sous-arbre d'un %s, niveau par niveau\n", varName))
+	sb.WriteString(fmt.Sprintf("func (r *%s) Descendants(%s) ([]models.%s, error) {\n", repoName, g.ctxParams(g.keyParams()), modelName))
+	g.writeTreeTenantValue(sb, "nil, ")
+	sb.WriteString(fmt.Sprintf("\tvar %s []models.%s\n", pluralName, modelName))
+	sb.WriteString(fmt.Sprintf("\terr %s %s.Raw(`WITH RECURSIVE descendants AS (\n", g.treeAssign(), g.repoDB()))
+	sb.WriteString(fmt.Sprintf("\t\tSELECT t.*, 1 AS depth FROM %s t WHERE t.%s = ?%s\n", table, parentColumn, g.treeTenant("t.")))
+	sb.WriteString("\t\tUNION ALL\n")
+	sb.WriteString(fmt.Sprintf("\t\tSELECT t.*, d.depth + 1 FROM %s t JOIN descendants d ON t.%s = d.%s%s\n",
+		table, parentColumn, keyColumn, g.treeTenant("t.")))
+	sb.WriteString(fmt.Sprintf("\t)\n\tSELECT * FROM descendants ORDER BY depth`, %s%s).Scan(&%s).Error\n",
+		g.keyArgs(), g.treeTenantArgs(2), pluralName))
+	sb.WriteString(fmt.Sprintf("\treturn %s, err\n", pluralName))
+	sb.WriteString("}\n\n")
+
+	// checkCycle: le nouveau parent ne doit pas avoir l'enregistrement parmi ses ancêtres
+	// (UNION dédoublonne les lignes, ce qui termine la requête même sur des données déjà cycliques)
+	sb.WriteString(fmt.Sprintf("// checkCycle vérifie que le parent d'un %s n'est ni lui-même ni l'un de ses descendants\n", varName))
+	sb.WriteString(fmt.Sprintf("func (r *%s) checkCycle(%s) error {\n", repoName, g.ctxParams(fmt.Sprintf("%s *models.%s", varName, modelName))))
+	g.writeTreeTenantValue(sb, "")
+	sb.WriteString("\tvar count int64\n")
+	sb.WriteString(fmt.Sprintf("\terr %s %s.Raw(`WITH RECURSIVE ancestors AS (\n", g.treeAssign(), g.repoDB()))
+	sb.WriteString(fmt.Sprintf("\t\tSELECT %s, %s FROM %s WHERE %s = ?%s\n", keyColumn, parentColumn, table, keyColumn, g.treeTenant("")))
+	sb.WriteString("\t\tUNION\n")
+	sb.WriteString(fmt.Sprintf("\t\tSELECT t.%s, t.%s FROM %s t JOIN ancestors a ON t.%s = a.%s%s\n",
+		keyColumn, parentColumn, table, keyColumn, parentColumn, g.treeTenant("t.")))
+	args := fmt.Sprintf("%s.%s", varName, inflection.Pascal(parentColumn))
+	if g.Schema.TenantScoped {
+		args += ", tenantID, tenantID"
+	}
+	sb.WriteString(fmt.Sprintf("\t)\n\tSELECT COUNT(*) FROM ancestors WHERE %s = ?`, %s, %s.%s).Scan(&count).Error\n",
+		keyColumn, args, varName, inflection.Pascal(keyColumn)))
+	sb.WriteString("\tif err != nil {\n")
+	sb.WriteString("\t\treturn err\n")
+	sb.WriteString("\t}\n")
+	sb.WriteString("\tif count > 0 {\n")
+	sb.WriteString(fmt.Sprintf("\t\treturn %s\n", g.treeCycleError()))
+	sb.WriteString("\t}\n")
+	sb.WriteString("\treturn nil\n")
+	sb.WriteString("}\n\n")
+
+	if g.Schema.TenantScoped {
+		g.writeTreeParentRepository(sb)
+	}
+}
+
+// writeTreeParentRepository écrit checkParent: le parent est lu par FindByID, donc
+// restreint au tenant de la requête
+func (g *Generator) writeTreeParentRepository(sb *strings.Builder) {
+	modelName := g.Schema.Model
+	varName := inflection.Camel(modelName)
+	col := g.treeParentColumn()
+	parent := varName + "." + inflection.Pascal(col.Name)
+
+	sb.WriteString(fmt.Sprintf("// checkParent vérifie que le parent d'un %s existe pour le tenant de la requête\n", varName))
+	sb.WriteString(fmt.Sprintf("func (r *%sRepository) checkParent(%s) error {\n", modelName, g.ctxParams(fmt.Sprintf("%s *models.%s", varName, modelName))))
+	value := parent
+	if col.Nullable {
+		sb.WriteString(fmt.Sprintf("\tif %s {\n", col.NullCheck(parent)))
+		sb.WriteString("\t\treturn nil\n")
+		sb.WriteString("\t}\n")
+		value = col.NullValue(parent)
+	}
+	sb.WriteString(fmt.Sprintf("\tif _, err := r.FindByID(%s); err != nil {\n", g.ctxArgs(value)))
+	sb.WriteString(fmt.Sprintf("\t\treturn %s\n", g.treeParentError()))
+	sb.WriteString("\t}\n")
+	sb.WriteString("\treturn nil\n")
+	sb.WriteString("}\n\n")
+}
+
+// writeTreeHandlers écrit les endpoints de parcours du sous-arbre
+func (g *Generator) writeTreeHandlers(sb *strings.Builder) {
+	for _, h := range []struct{ name, summary, description string }{
+		{"Ancestors", "Ancêtres d'un", "Récupère les ancêtres d'un %s, du parent direct jusqu'à la racine"},
+		{"Descendants", "Descendants d'un", "Récupère le sous-arbre d'un %s, niveau par niveau"},
+	} {
+		g.writeTreeHandler(sb, h.name, h.summary, h.description)
+	}
+}
+
+func (g *Generator) writeTreeHandler(sb *strings.Builder, name, summary, description string) {
+	modelName := g.Schema.Model
+	controllerName := modelName + "Controller"
+	varName := inflection.Camel(modelName)
+	pluralName := g.pluralVar()
+	route := strings.ToLower(name)
+
+	sb.WriteString(fmt.Sprintf("// %s "+strings.ToLower(description[:1])+description[1:]+"\n", name, varName))
+	sb.WriteString(fmt.Sprintf("// @Summary %s %s\n", summary, varName))
+	sb.WriteString("// @Description " + fmt.Sprintf(description, varName) + "\n")
+	sb.WriteString("// @Tags " + modelName + "\n")
+	sb.WriteString("// @Accept json\n")
+	sb.WriteString("// @Produce json\n")
+	g.writeKeyDocParams(sb)
+	sb.WriteString(fmt.Sprintf("// @Success 200 {object} map[string]interface{}\n"))
+	sb.WriteString("// @Router /" + g.resourceName() + g.keyDocRoute() + "/" + route + " [get]\n")
+	sb.WriteString(fmt.Sprintf("func (ctrl *%s) %s(c *gin.Context) {\n", controllerName, name))
+	g.writeKeyParsing(sb)
+	sb.WriteString(fmt.Sprintf("\tif _, err := %s; err != nil {\n", g.repoCall("FindByID", g.keyArgs())))
+	sb.WriteString("\t\tc.JSON(http.StatusNotFound, gin.H{\n")
+	sb.WriteString("\t\t\t\"error\": \"Enregistrement non trouvé\",\n")
+	sb.WriteString("\t\t})\n")
+	sb.WriteString("\t\treturn\n")
+	sb.WriteString("\t}\n\n")
+	sb.WriteString(fmt.Sprintf("\t%s, err := %s\n", pluralName, g.repoCall(name, g.keyArgs())))
+	sb.WriteString("\tif err != nil {\n")
+	sb.WriteString("\t\tc.JSON(http.StatusInternalServerError, gin.H{\n")
+	sb.WriteString("\t\t\t\"error\": \"Erreur lors de la récupération des données\",\n")
+	sb.WriteString("\t\t})\n")
+	sb.WriteString("\t\treturn\n")
+	sb.WriteString("\t}\n\n")
+	sb.WriteString("\tc.JSON(http.StatusOK, gin.H{\n")
+	sb.WriteString(fmt.Sprintf("\t\t\"data\": %s,\n", pluralName))
+	sb.WriteString("\t})\n")
+	sb.WriteString("}\n\n")
+}
+
+// writeTreeRoutes écrit les routes de parcours du sous-arbre
+func (g *Generator) writeTreeRoutes(sb *strings.Builder) {
+	varName := inflection.Camel(g.Schema.Model)
+	resourceName := g.resourceName()
+	keyRoute := g.keyRoute()
+	for _, name := range []string{"Ancestors", "Descendants"} {
+		path := keyRoute + "/" + strings.ToLower(name)
+		sb.WriteString(fmt.Sprintf("\t\t%sGroup.GET(\"%s\", ctrl.%s) // GET /%s%s\n",
+			varName, path, name, resourceName, path))
+	}
+}
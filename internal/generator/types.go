@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// GenerateTypes génère le package app/types utilisé par le style nullable "generic"
+func (g *Generator) GenerateTypes() error {
+	filename := filepath.Join("app", "types", "null.go")
+
+	// Créer le répertoire s'il n'existe pas
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, []byte(nullTypeContent), 0644)
+}
+
+// nullTypeContent contient le type générique Null[T]
+const nullTypeContent = `package types
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// Null représente une valeur pouvant être NULL en base de données.
+// En JSON, elle est sérialisée comme la valeur elle-même ou null.
+type Null[T any] struct {
+	V     T
+	Valid bool
+}
+
+// NewNull crée une valeur non nulle
+func NewNull[T any](v T) Null[T] {
+	return Null[T]{V: v, Valid: true}
+}
+
+// Ptr retourne un pointeur vers la valeur, ou nil si elle est NULL
+func (n Null[T]) Ptr() *T {
+	if !n.Valid {
+		return nil
+	}
+	v := n.V
+	return &v
+}
+
+// Scan implémente sql.Scanner
+func (n *Null[T]) Scan(value any) error {
+	var s sql.Null[T]
+	if err := s.Scan(value); err != nil {
+		return err
+	}
+	n.V, n.Valid = s.V, s.Valid
+	return nil
+}
+
+// Value implémente driver.Valuer
+func (n Null[T]) Value() (driver.Value, error) {
+	return sql.Null[T]{V: n.V, Valid: n.Valid}.Value()
+}
+
+// MarshalJSON implémente json.Marshaler
+func (n Null[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.V)
+}
+
+// UnmarshalJSON implémente json.Unmarshaler
+func (n *Null[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*n = Null[T]{}
+		return nil
+	}
+	if err := json.Unmarshal(data, &n.V); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+`
@@ -0,0 +1,158 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"go-scaffold/internal/inflection"
+)
+
+// Les schémas versioned: true portent une colonne version incrémentée à chaque mise à
+// jour. Update n'écrit que si la version en base est celle lue par le client (WHERE
+// version = ?) et renvoie sinon Err<Model>Conflict, traduite en 409 par le contrôleur.
+// La version est exposée en ETag et peut être imposée par l'en-tête If-Match, aux mises à
+// jour comme aux suppressions: Delete reçoit alors la version attendue et ne supprime
+// lui aussi qu'avec WHERE version = ?.
+
+// versionConflictError retourne le nom de l'erreur renvoyée lors d'un conflit de version
+func (g *Generator) versionConflictError() string {
+	return "Err" + g.Schema.Model + "Conflict"
+}
+
+// versionField retourne le champ Go de la colonne de version
+func (g *Generator) versionField() string {
+	col, _ := g.Schema.VersionField()
+	return inflection.Pascal(col.Name)
+}
+
+// writeVersionRepositoryError écrit l'erreur de conflit de version du repository
+func (g *Generator) writeVersionRepositoryError(sb *strings.Builder) {
+	varName := inflection.Camel(g.Schema.Model)
+	sb.WriteString(fmt.Sprintf("// %s est renvoyée lorsqu'un %s a été modifié depuis sa lecture\n", g.versionConflictError(), varName))
+	sb.WriteString(fmt.Sprintf("var %s = errors.New(\"le %s a été modifié entre-temps, rechargez-le avant de le modifier\")\n\n", g.versionConflictError(), varName))
+}
+
+// writeVersionBump écrit, dans Update, l'incrément de la version attendue
+func (g *Generator) writeVersionBump(sb *strings.Builder) {
+	varName := inflection.Camel(g.Schema.Model)
+	col, _ := g.Schema.VersionField()
+	sb.WriteString(fmt.Sprintf("\texpected := %s.%s\n", varName, g.versionField()))
+	sb.WriteString(fmt.Sprintf("\t%s.%s = expected + 1\n", varName, g.versionField()))
+	sb.WriteString(fmt.Sprintf("\tfields = append(fields, \"%s\")\n", col.Name))
+}
+
+// writeVersionedStatement écrit la mise à jour conditionnée par la version attendue;
+// indent est l'indentation courante (le conflit est renvoyé par return)
+func (g *Generator) writeVersionedStatement(sb *strings.Builder, db, indent string) {
+	varName := inflection.Camel(g.Schema.Model)
+	col, _ := g.Schema.VersionField()
+	sb.WriteString(fmt.Sprintf("%sresult := %s.Model(%s).Where(\"%s = ?\", expected).Select(fields).Updates(%s)\n", indent, db, varName, col.Name, varName))
+	sb.WriteString(fmt.Sprintf("%sif result.Error != nil {\n", indent))
+	sb.WriteString(fmt.Sprintf("%s\treturn result.Error\n", indent))
+	sb.WriteString(fmt.Sprintf("%s}\n", indent))
+	sb.WriteString(fmt.Sprintf("%sif result.RowsAffected == 0 {\n", indent))
+	sb.WriteString(fmt.Sprintf("%s\treturn %s\n", indent, g.versionConflictError()))
+	sb.WriteString(fmt.Sprintf("%s}\n", indent))
+}
+
+// writeVersionedUpdate écrit la fin de Update d'un schéma versioned sans audit
+func (g *Generator) writeVersionedUpdate(sb *strings.Builder) {
+	varName := inflection.Camel(g.Schema.Model)
+	col, _ := g.Schema.VersionField()
+	sb.WriteString(fmt.Sprintf("\tresult := %s.Model(%s).Where(\"%s = ?\", expected).Select(fields).Updates(%s)\n", g.repoDB(), varName, col.Name, varName))
+	sb.WriteString("\tif result.Error == nil && result.RowsAffected == 0 {\n")
+	sb.WriteString(fmt.Sprintf("\t\tresult.Error = %s\n", g.versionConflictError()))
+	sb.WriteString("\t}\n")
+	sb.WriteString("\tif result.Error != nil {\n")
+	sb.WriteString(fmt.Sprintf("\t\t%s.%s = expected\n", varName, g.versionField()))
+	sb.WriteString("\t}\n")
+	sb.WriteString("\treturn result.Error\n")
+}
+
+// writeVersionRollback rétablit la version attendue si la mise à jour a échoué
+func (g *Generator) writeVersionRollback(sb *strings.Builder, varName string) {
+	sb.WriteString("\tif err != nil {\n")
+	sb.WriteString(fmt.Sprintf("\t\t%s.%s = expected\n", varName, g.versionField()))
+	sb.WriteString("\t}\n")
+	sb.WriteString("\treturn err\n")
+}
+
+// deleteParams retourne les paramètres de Delete: la clé primaire, suivie pour un schéma
+// versioned de la version attendue facultative
+func (g *Generator) deleteParams() string {
+	if !g.Schema.Versioned {
+		return g.keyParams()
+	}
+	return g.keyParams() + ", version ...int64"
+}
+
+// writeVersionedDelete écrit la suppression de target par db, conditionnée par la version
+// attendue lorsqu'elle est fournie (Err<Model>Conflict si aucune ligne n'est supprimée);
+// indent est l'indentation courante
+func (g *Generator) writeVersionedDelete(sb *strings.Builder, db, target, indent string) {
+	col, _ := g.Schema.VersionField()
+	sb.WriteString(fmt.Sprintf("%squery := %s\n", indent, db))
+	sb.WriteString(fmt.Sprintf("%sif len(version) > 0 {\n", indent))
+	sb.WriteString(fmt.Sprintf("%s\tquery = query.Where(\"%s = ?\", version[0])\n", indent, col.Name))
+	sb.WriteString(fmt.Sprintf("%s}\n", indent))
+	sb.WriteString(fmt.Sprintf("%sresult := query.Delete(%s)\n", indent, target))
+	sb.WriteString(fmt.Sprintf("%sif result.Error != nil {\n", indent))
+	sb.WriteString(fmt.Sprintf("%s\treturn result.Error\n", indent))
+	sb.WriteString(fmt.Sprintf("%s}\n", indent))
+	sb.WriteString(fmt.Sprintf("%sif len(version) > 0 && result.RowsAffected == 0 {\n", indent))
+	sb.WriteString(fmt.Sprintf("%s\treturn %s\n", indent, g.versionConflictError()))
+	sb.WriteString(fmt.Sprintf("%s}\n", indent))
+}
+
+// writeETag écrit l'en-tête ETag dérivé de la version d'un model
+func (g *Generator) writeETag(sb *strings.Builder, varName string) {
+	sb.WriteString(fmt.Sprintf("\tc.Header(\"ETag\", \"\\\"\"+strconv.FormatInt(int64(%s.%s), 10)+\"\\\"\")\n", varName, g.versionField()))
+}
+
+// writeIfMatch écrit, dans un handler de mise à jour, la prise en compte de If-Match:
+// la version indiquée devient la version attendue par Update
+func (g *Generator) writeIfMatch(sb *strings.Builder) {
+	varName := inflection.Camel(g.Schema.Model)
+	col, _ := g.Schema.VersionField()
+	g.writeIfMatchVersion(sb)
+	version := "version"
+	if col.BaseGoType() != "int64" {
+		version = col.BaseGoType() + "(version)"
+	}
+	sb.WriteString(fmt.Sprintf("\t\t%s.%s = %s\n", varName, g.versionField(), version))
+	sb.WriteString("\t}\n\n")
+}
+
+// writeIfMatchDelete écrit, dans Delete, la vérification d'existence (404) et la lecture
+// de If-Match dans expected, la version transmise au repository
+func (g *Generator) writeIfMatchDelete(sb *strings.Builder) {
+	g.writeTenantExistence(sb)
+	sb.WriteString("\tvar expected []int64\n")
+	g.writeIfMatchVersion(sb)
+	sb.WriteString("\t\texpected = append(expected, version)\n")
+	sb.WriteString("\t}\n\n")
+}
+
+// writeIfMatchVersion ouvre le bloc qui lit la version de If-Match dans version (400 si
+// elle est invalide). If-Match: * désigne toute représentation existante: la version
+// n'est alors pas vérifiée.
+func (g *Generator) writeIfMatchVersion(sb *strings.Builder) {
+	sb.WriteString("\tif match := c.GetHeader(\"If-Match\"); match != \"\" && match != \"*\" {\n")
+	sb.WriteString("\t\tversion, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(match, \"W/\"), \"\\\"\"), 10, 64)\n")
+	sb.WriteString("\t\tif err != nil {\n")
+	sb.WriteString("\t\t\tc.JSON(http.StatusBadRequest, gin.H{\n")
+	sb.WriteString("\t\t\t\t\"error\": \"En-tête If-Match invalide\",\n")
+	sb.WriteString("\t\t\t})\n")
+	sb.WriteString("\t\t\treturn\n")
+	sb.WriteString("\t\t}\n")
+}
+
+// writeVersionConflict écrit, dans un handler de mise à jour, la réponse 409 au conflit de version
+func (g *Generator) writeVersionConflict(sb *strings.Builder) {
+	sb.WriteString(fmt.Sprintf("\t\tif errors.Is(err, repositories.%s) {\n", g.versionConflictError()))
+	sb.WriteString("\t\t\tc.JSON(http.StatusConflict, gin.H{\n")
+	sb.WriteString("\t\t\t\t\"error\": err.Error(),\n")
+	sb.WriteString("\t\t\t})\n")
+	sb.WriteString("\t\t\treturn\n")
+	sb.WriteString("\t\t}\n")
+}
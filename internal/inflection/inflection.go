@@ -0,0 +1,252 @@
+// Package inflection regroupe les conversions de noms utilisées par les générateurs:
+// casse (snake_case, PascalCase, camelCase) en respectant les initialismes Go
+// (ID, URL, API), et pluralisation anglaise avec pluriels irréguliers et mots invariables.
+package inflection
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+type rule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// pluralRules et singularRules sont appliquées dans l'ordre: la première qui correspond l'emporte
+var pluralRules = compile([][2]string{
+	{`(quiz)$`, "${1}zes"},
+	{`^(ox)$`, "${1}en"},
+	{`(matr|vert|ind)(?:ix|ex)$`, "${1}ices"},
+	{`(x|ch|ss|sh)$`, "${1}es"},
+	{`([^aeiouy]|qu)y$`, "${1}ies"},
+	{`(hive)$`, "${1}s"},
+	{`([^f])fe$`, "${1}ves"},
+	{`([lr]|lea|loa|thie)f$`, "${1}ves"},
+	{`sis$`, "ses"},
+	{`([ti])um$`, "${1}a"},
+	{`(bus|alias|status|campus)$`, "${1}es"},
+	{`(octop|vir)us$`, "${1}i"},
+	{`(ax|test)is$`, "${1}es"},
+	{`s$`, "s"},
+	{`$`, "s"},
+})
+
+var singularRules = compile([][2]string{
+	{`(quiz)zes$`, "${1}"},
+	{`^(ox)en$`, "${1}"},
+	{`(matr)ices$`, "${1}ix"},
+	{`(vert|ind)ices$`, "${1}ex"},
+	{`(x|ch|ss|sh)es$`, "${1}"},
+	{`([^aeiouy]|qu)ies$`, "${1}y"},
+	{`(hive)s$`, "${1}"},
+	{`([lr]|lea|loa|thie)ves$`, "${1}f"},
+	{`([^f])ves$`, "${1}fe"},
+	{`(analy|ba|diagno|parenthe|progno|synop|the)ses$`, "${1}sis"},
+	{`([ti])a$`, "${1}um"},
+	{`(bus|alias|status|campus)es$`, "${1}"},
+	{`(octop|vir)i$`, "${1}us"},
+	{`(ax|test)es$`, "${1}is"},
+	{`ss$`, "ss"},
+	{`s$`, ""},
+})
+
+// irregulars associe le singulier au pluriel des mots irréguliers
+var irregulars = map[string]string{
+	"person": "people",
+	"man":    "men",
+	"woman":  "women",
+	"child":  "children",
+	"mouse":  "mice",
+	"goose":  "geese",
+	"foot":   "feet",
+	"tooth":  "teeth",
+}
+
+// uncountables contient les mots invariables
+var uncountables = map[string]bool{
+	"equipment":   true,
+	"information": true,
+	"rice":        true,
+	"money":       true,
+	"species":     true,
+	"series":      true,
+	"fish":        true,
+	"sheep":       true,
+	"news":        true,
+	"metadata":    true,
+	"feedback":    true,
+}
+
+// initialisms contient les sigles écrits en majuscules dans les identifiants Go
+var initialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true,
+	"EOF": true, "GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true,
+	"IP": true, "JSON": true, "QPS": true, "RAM": true, "RPC": true, "SLA": true,
+	"SMTP": true, "SQL": true, "SSH": true, "TCP": true, "TLS": true, "TTL": true,
+	"UDP": true, "UI": true, "UID": true, "UUID": true, "URI": true, "URL": true,
+	"UTF8": true, "VM": true, "XML": true, "XMPP": true, "XSRF": true, "XSS": true,
+}
+
+func compile(rules [][2]string) []rule {
+	compiled := make([]rule, len(rules))
+	for i, r := range rules {
+		compiled[i] = rule{regexp.MustCompile(r[0]), r[1]}
+	}
+	return compiled
+}
+
+// AddIrregular enregistre un pluriel irrégulier (ex: cactus, cacti)
+func AddIrregular(singular, plural string) {
+	irregulars[strings.ToLower(singular)] = strings.ToLower(plural)
+}
+
+// AddUncountable enregistre des mots invariables
+func AddUncountable(words ...string) {
+	for _, word := range words {
+		uncountables[strings.ToLower(word)] = true
+	}
+}
+
+// AddInitialism enregistre des sigles à écrire en majuscules (ex: SKU)
+func AddInitialism(words ...string) {
+	for _, word := range words {
+		initialisms[strings.ToUpper(word)] = true
+	}
+}
+
+// Pluralize retourne le pluriel d'un nom (category -> categories, OrderLine -> OrderLines).
+// Seul le dernier mot est accordé.
+func Pluralize(word string) string {
+	return inflect(word, func(last string) string {
+		if plural, ok := irregulars[last]; ok {
+			return plural
+		}
+		return applyRules(pluralRules, last)
+	})
+}
+
+// Singularize retourne le singulier d'un nom (categories -> category, people -> person)
+func Singularize(word string) string {
+	return inflect(word, func(last string) string {
+		for singular, plural := range irregulars {
+			if plural == last {
+				return singular
+			}
+		}
+		return applyRules(singularRules, last)
+	})
+}
+
+// inflect applique fn au dernier mot de word en conservant sa casse
+func inflect(word string, fn func(string) string) string {
+	start := lastWordStart(word)
+	prefix, last := word[:start], word[start:]
+	lower := strings.ToLower(last)
+	if last == "" || uncountables[lower] {
+		return word
+	}
+
+	result := fn(lower)
+	if len(last) > 1 && last == strings.ToUpper(last) && !strings.HasPrefix(result, lower) {
+		return prefix + strings.ToUpper(result)
+	}
+	// La casse d'origine est conservée sur la partie commune (URL -> URLs, URLs -> URL)
+	n := 0
+	for n < len(result) && n < len(lower) && result[n] == lower[n] {
+		n++
+	}
+	if n == 0 && unicode.IsUpper(rune(last[0])) {
+		return prefix + strings.ToUpper(result[:1]) + result[1:]
+	}
+	return prefix + last[:n] + result[n:]
+}
+
+// lastWordStart retourne la position du dernier mot d'un nom snake_case ou PascalCase; un
+// sigle se termine avant la majuscule d'un mot (APIKey -> Key), sauf devant le s final
+// de son pluriel (UserIDs -> IDs)
+func lastWordStart(word string) int {
+	start := strings.LastIndexAny(word, "_- ") + 1
+	for i := len(word) - 1; i > start; i-- {
+		if !isUpper(word[i]) {
+			continue
+		}
+		if !isUpper(word[i-1]) {
+			return i
+		}
+		if i+1 < len(word) && !isUpper(word[i+1]) && word[i+1:] != "s" {
+			return i
+		}
+	}
+	return start
+}
+
+func applyRules(rules []rule, word string) string {
+	for _, r := range rules {
+		if r.pattern.MatchString(word) {
+			return r.pattern.ReplaceAllString(word, r.replacement)
+		}
+	}
+	return word
+}
+
+// Snake convertit un nom en snake_case (OrderLine -> order_line, APIKey -> api_key, UserID -> user_id)
+func Snake(s string) string {
+	runes := []rune(s)
+	var result strings.Builder
+	for i, r := range runes {
+		if r == '-' || r == ' ' {
+			r = '_'
+		}
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prev != '_' && prev != '-' && prev != ' ' &&
+				(unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower)) {
+				result.WriteRune('_')
+			}
+		}
+		result.WriteRune(unicode.ToLower(r))
+	}
+	return result.String()
+}
+
+// Pascal convertit un nom en PascalCase en respectant les initialismes (api_id -> APIID, user_id -> UserID)
+func Pascal(s string) string {
+	words := words(s)
+	for i, word := range words {
+		words[i] = capitalize(word)
+	}
+	return strings.Join(words, "")
+}
+
+// Camel convertit un nom en camelCase (user_id -> userID, id -> id)
+func Camel(s string) string {
+	words := words(s)
+	for i, word := range words {
+		if i > 0 {
+			words[i] = capitalize(word)
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// words découpe un nom en mots en minuscules
+func words(s string) []string {
+	return strings.FieldsFunc(Snake(s), func(r rune) bool {
+		return r == '_'
+	})
+}
+
+// capitalize met en forme un mot en minuscules (sigle en majuscules, sinon initiale en majuscule)
+func capitalize(word string) string {
+	if upper := strings.ToUpper(word); initialisms[upper] {
+		return upper
+	}
+	return strings.ToUpper(word[:1]) + word[1:]
+}
+
+func isUpper(b byte) bool {
+	return b >= 'A' && b <= 'Z'
+}
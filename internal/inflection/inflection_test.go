@@ -0,0 +1,150 @@
+package inflection
+
+import "testing"
+
+func TestPluralize(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		// Règles
+		{"post", "posts"},
+		{"category", "categories"},
+		{"day", "days"},
+		{"status", "statuses"},
+		{"box", "boxes"},
+		{"match", "matches"},
+		{"address", "addresses"},
+		{"matrix", "matrices"},
+		{"index", "indices"},
+		{"hive", "hives"},
+		{"knife", "knives"},
+		{"leaf", "leaves"},
+		{"analysis", "analyses"},
+		{"medium", "media"},
+		{"quiz", "quizzes"},
+		{"ox", "oxen"},
+		// Irréguliers
+		{"person", "people"},
+		{"child", "children"},
+		{"Person", "People"},
+		// Invariables
+		{"series", "series"},
+		{"metadata", "metadata"},
+		{"news", "news"},
+		// Noms composés: seul le dernier mot est accordé
+		{"OrderLine", "OrderLines"},
+		{"order_line", "order_lines"},
+		{"SalesPerson", "SalesPeople"},
+		{"product_category", "product_categories"},
+		{"TvSeries", "TvSeries"},
+		// Sigles
+		{"URL", "URLs"},
+		{"APIKey", "APIKeys"},
+		{"UserID", "UserIDs"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Pluralize(tt.word); got != tt.want {
+			t.Errorf("Pluralize(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestSingularize(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"posts", "post"},
+		{"categories", "category"},
+		{"statuses", "status"},
+		{"boxes", "box"},
+		{"addresses", "address"},
+		{"matrices", "matrix"},
+		{"indices", "index"},
+		{"hives", "hive"},
+		{"knives", "knife"},
+		{"leaves", "leaf"},
+		{"analyses", "analysis"},
+		{"media", "medium"},
+		{"quizzes", "quiz"},
+		{"oxen", "ox"},
+		{"address", "address"},
+		{"people", "person"},
+		{"children", "child"},
+		{"series", "series"},
+		{"metadata", "metadata"},
+		{"OrderLines", "OrderLine"},
+		{"order_lines", "order_line"},
+		{"URLs", "URL"},
+		{"APIKeys", "APIKey"},
+		{"UserIDs", "UserID"},
+	}
+	for _, tt := range tests {
+		if got := Singularize(tt.word); got != tt.want {
+			t.Errorf("Singularize(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestCase(t *testing.T) {
+	tests := []struct {
+		name   string
+		snake  string
+		pascal string
+		camel  string
+	}{
+		{"OrderLine", "order_line", "OrderLine", "orderLine"},
+		{"order_line", "order_line", "OrderLine", "orderLine"},
+		{"user_id", "user_id", "UserID", "userID"},
+		{"UserID", "user_id", "UserID", "userID"},
+		{"id", "id", "ID", "id"},
+		{"APIKey", "api_key", "APIKey", "apiKey"},
+		{"api_id", "api_id", "APIID", "apiID"},
+		{"HTTPServer", "http_server", "HTTPServer", "httpServer"},
+		{"image_url", "image_url", "ImageURL", "imageURL"},
+		{"ip-address", "ip_address", "IPAddress", "ipAddress"},
+		{"Address2", "address2", "Address2", "address2"},
+	}
+	for _, tt := range tests {
+		if got := Snake(tt.name); got != tt.snake {
+			t.Errorf("Snake(%q) = %q, want %q", tt.name, got, tt.snake)
+		}
+		if got := Pascal(tt.name); got != tt.pascal {
+			t.Errorf("Pascal(%q) = %q, want %q", tt.name, got, tt.pascal)
+		}
+		if got := Camel(tt.name); got != tt.camel {
+			t.Errorf("Camel(%q) = %q, want %q", tt.name, got, tt.camel)
+		}
+	}
+}
+
+// Les règles ajoutées par go-scaffold.yaml (section inflections) passent par Add*
+func TestAdd(t *testing.T) {
+	AddIrregular("Cactus", "Cacti")
+	AddUncountable("Equipment", "firmware")
+	AddInitialism("sku")
+
+	tests := []struct {
+		fn   func(string) string
+		name string
+		word string
+		want string
+	}{
+		{Pluralize, "Pluralize", "cactus", "cacti"},
+		{Pluralize, "Pluralize", "GardenCactus", "GardenCacti"},
+		{Singularize, "Singularize", "cacti", "cactus"},
+		{Pluralize, "Pluralize", "firmware", "firmware"},
+		{Pluralize, "Pluralize", "DeviceFirmware", "DeviceFirmware"},
+		{Singularize, "Singularize", "firmware", "firmware"},
+		{Pascal, "Pascal", "product_sku", "ProductSKU"},
+		{Camel, "Camel", "sku_code", "skuCode"},
+		{Camel, "Camel", "product_sku", "productSKU"},
+	}
+	for _, tt := range tests {
+		if got := tt.fn(tt.word); got != tt.want {
+			t.Errorf("%s(%q) = %q, want %q", tt.name, tt.word, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package parser
+
+import "fmt"
+
+// VisibleColumns retourne les colonnes sérialisées en JSON, c'est-à-dire sans les colonnes hidden
+func (s *Schema) VisibleColumns() []Column {
+	var columns []Column
+	for _, col := range s.Columns {
+		if !col.Hidden {
+			columns = append(columns, col)
+		}
+	}
+	return columns
+}
+
+// validateHidden vérifie qu'une colonne hidden ne peut être ni renvoyée ni devinée par la liste
+func validateHidden(schema *Schema) error {
+	for _, col := range schema.Columns {
+		if !col.Hidden {
+			continue
+		}
+		if col.Primary {
+			return fmt.Errorf("la colonne %s de la clé primaire ne peut pas être hidden", col.Name)
+		}
+		if col.Filterable || col.Sortable || col.Searchable {
+			return fmt.Errorf("la colonne hidden %s ne peut pas être filterable, sortable ou searchable", col.Name)
+		}
+		if schema.CursorPagination() && schema.CursorColumn().Name == col.Name {
+			return fmt.Errorf("la colonne hidden %s ne peut pas servir de curseur de pagination", col.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package parser
+
+import "testing"
+
+func TestValidateHidden(t *testing.T) {
+	checkErrors(t, []struct{ name, schema, err string }{
+		{"colonne hidden", `
+table: users
+model: User
+columns: [{name: id, type: bigint}, {name: password_hash, type: string, hidden: true}]`, ""},
+		{"clé primaire", `
+table: users
+model: User
+columns: [{name: id, type: bigint, primary: true, hidden: true}]`, "clé primaire ne peut pas être hidden"},
+		{"filterable", `
+table: users
+model: User
+columns: [{name: id, type: bigint}, {name: token, type: string, hidden: true, filterable: true}]`, "ne peut pas être filterable, sortable ou searchable"},
+		{"searchable", `
+table: users
+model: User
+columns: [{name: id, type: bigint}, {name: token, type: string, hidden: true, searchable: true}]`, "ne peut pas être filterable, sortable ou searchable"},
+		{"curseur", `
+table: users
+model: User
+pagination: {mode: cursor, column: rank}
+columns: [{name: id, type: bigint}, {name: rank, type: integer, hidden: true}]`, "ne peut pas servir de curseur"},
+	})
+}
+
+func TestVisibleColumns(t *testing.T) {
+	schema, err := parse(t, `
+table: users
+model: User
+columns: [{name: id, type: bigint}, {name: email, type: email}, {name: password_hash, type: string, hidden: true}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, col := range schema.VisibleColumns() {
+		names = append(names, col.Name)
+	}
+	if len(names) != 2 || names[0] != "id" || names[1] != "email" {
+		t.Errorf("VisibleColumns() = %v, want [id email]", names)
+	}
+}
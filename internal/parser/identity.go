@@ -0,0 +1,91 @@
+package parser
+
+import "fmt"
+
+// Stratégies de génération de la clé primaire (option id_strategy du schéma)
+const (
+	IDAutoIncrement = "auto_increment" // Séquence de la base de données
+	IDUUIDv4        = "uuid"           // UUID v4 aléatoire
+	IDUUIDv7        = "uuid_v7"        // UUID v7, ordonné dans le temps
+	IDULID          = "ulid"           // ULID, ordonné dans le temps
+	IDNone          = "none"           // Clé fournie par le client
+)
+
+// normalizeIDStrategy accepte les alias des stratégies (uuid_v4)
+func normalizeIDStrategy(strategy string) string {
+	if strategy == "uuid_v4" {
+		return IDUUIDv4
+	}
+	return strategy
+}
+
+// KeyStrategy retourne la stratégie de génération de la clé primaire.
+// Sans id_strategy: auto-incrément, UUID v4 pour une clé uuid, ULID pour une clé ulid,
+// et clé fournie par le client dans les autres cas (dont les clés composites).
+func (s *Schema) KeyStrategy() string {
+	keys := s.PrimaryKeys()
+	if len(keys) != 1 {
+		return IDNone
+	}
+	key := keys[0]
+	if key.AutoIncrement {
+		return IDAutoIncrement
+	}
+	if s.IDStrategy != "" {
+		return normalizeIDStrategy(s.IDStrategy)
+	}
+	switch key.Type {
+	case "uuid":
+		return IDUUIDv4
+	case "ulid":
+		return IDULID
+	}
+	return IDNone
+}
+
+// expandIDStrategy marque la clé primaire auto-incrémentée de id_strategy: auto_increment
+func expandIDStrategy(schema *Schema) {
+	if normalizeIDStrategy(schema.IDStrategy) != IDAutoIncrement {
+		return
+	}
+	for i, col := range schema.Columns {
+		if schema.IsPrimaryKey(col.Name) {
+			schema.Columns[i].AutoIncrement = true
+		}
+	}
+}
+
+// validateIDStrategy vérifie que la stratégie est compatible avec la clé primaire
+func validateIDStrategy(schema *Schema) error {
+	strategy := normalizeIDStrategy(schema.IDStrategy)
+	if strategy == "" {
+		return nil
+	}
+
+	keys := schema.PrimaryKeys()
+	switch strategy {
+	case IDNone:
+		return nil
+	case IDAutoIncrement, IDUUIDv4, IDUUIDv7, IDULID:
+	default:
+		return fmt.Errorf("id_strategy inconnue: %s (auto_increment, uuid, uuid_v7, ulid, none)", schema.IDStrategy)
+	}
+	if len(keys) != 1 {
+		return fmt.Errorf("id_strategy %s n'est pas supportée avec une clé primaire composite", strategy)
+	}
+
+	key := keys[0]
+	if strategy == IDAutoIncrement {
+		if !key.IsZeroable() || key.IsDecimal() || key.BaseGoType() == "bool" {
+			return fmt.Errorf("id_strategy auto_increment nécessite une clé primaire entière (%s est %s)", key.Name, key.Type)
+		}
+		return nil
+	}
+	if key.AutoIncrement {
+		return fmt.Errorf("id_strategy %s est incompatible avec auto_increment sur %s", strategy, key.Name)
+	}
+	if key.BaseGoType() != "string" {
+		return fmt.Errorf("id_strategy %s nécessite une clé primaire de type texte (uuid, ulid, string)", strategy)
+	}
+	return nil
+}
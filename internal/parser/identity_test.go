@@ -0,0 +1,80 @@
+package parser
+
+import "testing"
+
+func TestKeyStrategy(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   string
+		err    string
+	}{
+		{"auto-incrément", `
+table: posts
+model: Post
+columns: [{name: id, type: bigint, primary: true, auto_increment: true}]`, IDAutoIncrement, ""},
+		{"clé uuid", `
+table: posts
+model: Post
+columns: [{name: id, type: uuid, primary: true}]`, IDUUIDv4, ""},
+		{"clé ulid", `
+table: posts
+model: Post
+columns: [{name: id, type: ulid, primary: true}]`, IDULID, ""},
+		{"uuid_v7", `
+table: posts
+model: Post
+id_strategy: uuid_v7
+columns: [{name: id, type: uuid, primary: true}]`, IDUUIDv7, ""},
+		{"alias uuid_v4", `
+table: posts
+model: Post
+id_strategy: uuid_v4
+columns: [{name: id, type: string, size: 36, primary: true}]`, IDUUIDv4, ""},
+		{"auto_increment explicite", `
+table: posts
+model: Post
+id_strategy: auto_increment
+columns: [{name: id, type: bigint, primary: true}]`, IDAutoIncrement, ""},
+		{"clé fournie", `
+table: countries
+model: Country
+columns: [{name: code, type: string, size: 2, primary: true}]`, IDNone, ""},
+		{"clé composite", `
+table: order_lines
+model: OrderLine
+columns: [{name: order_id, type: string, primary: true}, {name: line_no, type: integer, primary: true}]`, IDNone, ""},
+		{"stratégie inconnue", `
+table: posts
+model: Post
+id_strategy: snowflake
+columns: [{name: id, type: bigint, primary: true}]`, "", "id_strategy inconnue"},
+		{"uuid sur une clé entière", `
+table: posts
+model: Post
+id_strategy: uuid
+columns: [{name: id, type: bigint, primary: true}]`, "", "nécessite une clé primaire de type texte"},
+		{"auto_increment sur une clé texte", `
+table: posts
+model: Post
+id_strategy: auto_increment
+columns: [{name: id, type: uuid, primary: true}]`, "", "nécessite une clé primaire entière"},
+		{"uuid avec auto_increment", `
+table: posts
+model: Post
+id_strategy: ulid
+columns: [{name: id, type: string, primary: true, auto_increment: true}]`, "", "incompatible avec auto_increment"},
+		{"stratégie sur une clé composite", `
+table: order_lines
+model: OrderLine
+id_strategy: uuid
+columns: [{name: order_id, type: string, primary: true}, {name: line_no, type: integer, primary: true}]`, "", "clé primaire composite"},
+	}
+	for _, tt := range tests {
+		schema, err := parse(t, tt.schema)
+		check(t, tt.name, err, tt.err)
+		if err == nil && schema.KeyStrategy() != tt.want {
+			t.Errorf("%s: KeyStrategy() = %q, want %q", tt.name, schema.KeyStrategy(), tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package parser
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Modes de pagination de la liste
+const (
+	PaginationOffset = "offset" // page et page_size, total compté (par défaut)
+	PaginationCursor = "cursor" // keyset: curseurs opaques next_cursor et prev_cursor
+)
+
+// Pagination décrit la pagination de la liste d'un schéma. La forme courte
+// "pagination: cursor" est acceptée.
+type Pagination struct {
+	Mode      string `yaml:"mode"`       // offset ou cursor
+	Column    string `yaml:"column"`     // Colonne de tri du curseur (clé primaire par défaut)
+	Order     string `yaml:"order"`      // asc (par défaut) ou desc
+	SkipTotal bool   `yaml:"skip_total"` // Ne pas compter le total des enregistrements
+}
+
+// UnmarshalYAML accepte "pagination: cursor" comme "pagination: {mode: cursor}"
+func (p *Pagination) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		p.Mode = value.Value
+		return nil
+	}
+	type plain Pagination
+	return value.Decode((*plain)(p))
+}
+
+// CursorPagination indique si la liste est paginée par curseur
+func (s *Schema) CursorPagination() bool {
+	return s.Pagination.Mode == PaginationCursor
+}
+
+// CursorColumn retourne la colonne de tri du curseur: la colonne déclarée, ou la clé primaire
+func (s *Schema) CursorColumn() Column {
+	name := s.Pagination.Column
+	if name == "" {
+		name = s.PrimaryKeys()[0].Name
+	}
+	for _, col := range s.Columns {
+		if col.Name == name {
+			return col
+		}
+	}
+	return Column{}
+}
+
+// CursorDescending indique si le curseur parcourt la liste par ordre décroissant
+func (s *Schema) CursorDescending() bool {
+	return s.Pagination.Order == "desc"
+}
+
+// validatePagination vérifie le mode de pagination et la colonne du curseur
+func validatePagination(schema *Schema) error {
+	p := schema.Pagination
+	switch p.Mode {
+	case "", PaginationOffset:
+		if p.Column != "" || p.Order != "" {
+			return fmt.Errorf("pagination.column et pagination.order ne s'appliquent qu'à la pagination cursor")
+		}
+		return nil
+	case PaginationCursor:
+	default:
+		return fmt.Errorf("mode de pagination non supporté: %s (offset ou cursor)", p.Mode)
+	}
+
+	if len(schema.PrimaryKeys()) != 1 {
+		return fmt.Errorf("la pagination cursor requiert une clé primaire sur une seule colonne")
+	}
+	if p.Order != "" && p.Order != "asc" && p.Order != "desc" {
+		return fmt.Errorf("ordre de pagination non supporté: %s (asc ou desc)", p.Order)
+	}
+	if p.Column != "" {
+		if !schema.HasColumn(p.Column) {
+			return fmt.Errorf("colonne de pagination inconnue: %s", p.Column)
+		}
+		col := schema.CursorColumn()
+		if col.Nullable || col.FilterKind() == "" {
+			return fmt.Errorf("la colonne de pagination %s doit être non nullable et comparable", p.Column)
+		}
+	}
+	for _, col := range schema.Columns {
+		if col.Sortable {
+			return fmt.Errorf("la colonne %s ne peut pas être sortable: l'ordre d'une pagination cursor est fixé par pagination.column", col.Name)
+		}
+	}
+	return nil
+}
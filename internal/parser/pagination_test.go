@@ -0,0 +1,74 @@
+package parser
+
+import "testing"
+
+func TestValidatePagination(t *testing.T) {
+	tests := []struct {
+		name       string
+		schema     string
+		column     string
+		descending bool
+		err        string
+	}{
+		{"forme courte", `
+table: events
+model: Event
+pagination: cursor
+columns: [{name: id, type: bigint}]`, "id", false, ""},
+		{"colonne et ordre", `
+table: events
+model: Event
+pagination: {mode: cursor, column: created_at, order: desc, skip_total: true}
+columns: [{name: id, type: bigint}, {name: created_at, type: timestamp}]`, "created_at", true, ""},
+		{"offset", `
+table: events
+model: Event
+pagination: offset
+columns: [{name: id, type: bigint}]`, "id", false, ""},
+		{"mode inconnu", `
+table: events
+model: Event
+pagination: keyset
+columns: [{name: id, type: bigint}]`, "", false, "mode de pagination non supporté"},
+		{"colonne en offset", `
+table: events
+model: Event
+pagination: {column: created_at}
+columns: [{name: id, type: bigint}, {name: created_at, type: timestamp}]`, "", false, "ne s'appliquent qu'à la pagination cursor"},
+		{"ordre inconnu", `
+table: events
+model: Event
+pagination: {mode: cursor, order: random}
+columns: [{name: id, type: bigint}]`, "", false, "ordre de pagination non supporté"},
+		{"colonne inconnue", `
+table: events
+model: Event
+pagination: {mode: cursor, column: missing}
+columns: [{name: id, type: bigint}]`, "", false, "colonne de pagination inconnue"},
+		{"colonne nullable", `
+table: events
+model: Event
+pagination: {mode: cursor, column: published_at}
+columns: [{name: id, type: bigint}, {name: published_at, type: timestamp, nullable: true}]`, "", false, "doit être non nullable et comparable"},
+		{"clé composite", `
+table: order_lines
+model: OrderLine
+pagination: cursor
+columns: [{name: order_id, type: string, primary: true}, {name: line_no, type: integer, primary: true}]`, "", false, "clé primaire sur une seule colonne"},
+		{"colonne sortable", `
+table: events
+model: Event
+pagination: cursor
+columns: [{name: id, type: bigint}, {name: title, type: string, sortable: true}]`, "", false, "ne peut pas être sortable"},
+	}
+	for _, tt := range tests {
+		schema, err := parse(t, tt.schema)
+		check(t, tt.name, err, tt.err)
+		if err != nil {
+			continue
+		}
+		if got := schema.CursorColumn().Name; got != tt.column || schema.CursorDescending() != tt.descending {
+			t.Errorf("%s: curseur = %s (desc=%v), want %s (desc=%v)", tt.name, got, schema.CursorDescending(), tt.column, tt.descending)
+		}
+	}
+}
@@ -3,18 +3,48 @@ package parser
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
+// Dialectes SQL supportés
+const (
+	DialectPostgres = "postgres"
+	DialectMySQL    = "mysql"
+	DialectSQLite   = "sqlite"
+)
+
+// projectDialect est le dialecte SQL du projet, dont dépendent certaines validations
+var projectDialect = DialectPostgres
+
+// SetDialect définit le dialecte SQL du projet
+func SetDialect(dialect string) {
+	if dialect != "" {
+		projectDialect = dialect
+	}
+}
+
+// SoftDeleteColumn est la colonne de suppression logique
+const SoftDeleteColumn = "deleted_at"
+
 // Schema représente la structure complète d'un schéma de table
 type Schema struct {
-	Table       string       `yaml:"table"`
-	Model       string       `yaml:"model"`
-	Columns     []Column     `yaml:"columns"`
-	Relations   []Relation   `yaml:"relations"`
-	Indexes     []Index      `yaml:"indexes"`
-	Validations []Validation `yaml:"validations"`
+	Table        string       `yaml:"table"`
+	Model        string       `yaml:"model"`
+	Columns      []Column     `yaml:"columns"`
+	Relations    []Relation   `yaml:"relations"`
+	Indexes      []Index      `yaml:"indexes"`
+	Validations  []Validation `yaml:"validations"`
+	Scopes       []Scope      `yaml:"scopes"`
+	Tree         bool         `yaml:"tree"`          // Hiérarchie via la relation belongs_to vers le même model
+	SoftDeletes  bool         `yaml:"soft_deletes"`  // Suppression logique via la colonne deleted_at
+	IDStrategy   string       `yaml:"id_strategy"`   // auto_increment, uuid, uuid_v7, ulid ou none
+	Events       bool         `yaml:"events"`        // Événements Created/Updated/Deleted émis par les hooks GORM
+	Audit        bool         `yaml:"audit"`         // Historique des modifications dans la table audits
+	Versioned    bool         `yaml:"versioned"`     // Verrouillage optimiste via la colonne version
+	TenantScoped bool         `yaml:"tenant_scoped"` // Requêtes restreintes au tenant du contexte
+	Pagination   Pagination   `yaml:"pagination"`    // offset (par défaut) ou cursor
 }
 
 // Column représente une colonne de table
@@ -22,22 +52,38 @@ type Column struct {
 	Name          string      `yaml:"name"`
 	Type          string      `yaml:"type"`
 	Size          int         `yaml:"size"`
+	Precision     int         `yaml:"precision"` // Nombre total de chiffres (decimal, money)
+	Scale         int         `yaml:"scale"`     // Nombre de chiffres après la virgule (decimal, money)
+	Currency      string      `yaml:"currency"`  // Colonne de devise associée (money)
 	Primary       bool        `yaml:"primary"`
 	AutoIncrement bool        `yaml:"auto_increment"`
 	Nullable      bool        `yaml:"nullable"`
 	Unique        bool        `yaml:"unique"`
 	Default       interface{} `yaml:"default"`
 	Comment       string      `yaml:"comment"`
+	Filterable    bool        `yaml:"filterable"` // Filtrable sur la liste (?filter[colonne])
+	Sortable      bool        `yaml:"sortable"`   // Triable sur la liste (?sort=colonne)
+	Searchable    bool        `yaml:"searchable"` // Incluse dans la recherche plein texte (?q=)
+	Hidden        bool        `yaml:"hidden"`     // Jamais sérialisée en JSON ni sélectionnable (?fields=)
+	SoftDelete    bool        `yaml:"-"`          // Colonne deleted_at d'un schéma soft_deletes
+	Version       bool        `yaml:"-"`          // Colonne version d'un schéma versioned
+	Tenant        bool        `yaml:"-"`          // Colonne de tenant d'un schéma tenant_scoped
+	Morph         bool        `yaml:"-"`          // Colonne <nom>_id ajoutée pour une relation morph_to
 }
 
 // Relation représente une relation entre tables
 type Relation struct {
-	Type        string `yaml:"type"` // belongs_to, has_many, has_one, many_to_many
-	Model       string `yaml:"model"`
-	ForeignKey  string `yaml:"foreign_key"`
-	References  string `yaml:"references"`
-	PivotTable  string `yaml:"pivot_table"`
-	RelatedKey  string `yaml:"related_key"`
+	Type        string   `yaml:"type"` // belongs_to, has_many, has_one, many_to_many, morph_to, morph_many, morph_one
+	Model       string   `yaml:"model"`
+	Name        string   `yaml:"name"` // Nom du champ (ex: author, parent) ou de la relation polymorphe (ex: commentable)
+	Models      []string `yaml:"models"` // Models parents autorisés (morph_to)
+	ForeignKey  string   `yaml:"foreign_key"`
+	References  string   `yaml:"references"`
+	PivotTable  string   `yaml:"pivot_table"`
+	RelatedKey  string   `yaml:"related_key"`
+	OnDelete    string   `yaml:"on_delete"` // cascade, restrict, set null, no action
+	OnUpdate    string   `yaml:"on_update"` // cascade, restrict, set null, no action
+	Preload     bool     `yaml:"preload"` // Chargée par défaut quand ?include= est absent
 }
 
 // Index représente un index de base de données
@@ -65,6 +111,13 @@ func ParseSchema(filename string) (*Schema, error) {
 		return nil, fmt.Errorf("erreur de parsing YAML: %w", err)
 	}
 
+	expandMoneyColumns(&schema)
+	expandMorphColumns(&schema)
+	expandSoftDeletes(&schema)
+	expandVersion(&schema)
+	expandTenant(&schema)
+	expandIDStrategy(&schema)
+
 	// Validation du schéma
 	if err := validateSchema(&schema); err != nil {
 		return nil, err
@@ -84,51 +137,349 @@ func validateSchema(schema *Schema) error {
 	if len(schema.Columns) == 0 {
 		return fmt.Errorf("au moins une colonne est requise")
 	}
+	keys := schema.PrimaryKeys()
+	if len(keys) == 0 {
+		return fmt.Errorf("aucune clé primaire: marquez une colonne primary ou déclarez une colonne id")
+	}
+	for _, key := range keys {
+		if key.Nullable {
+			return fmt.Errorf("la colonne %s de la clé primaire ne peut pas être nullable", key.Name)
+		}
+	}
+	if err := validateVersion(schema); err != nil {
+		return err
+	}
+	if err := validateTenant(schema); err != nil {
+		return err
+	}
+	if err := validateQueryColumns(schema); err != nil {
+		return err
+	}
+	if err := validateSearch(schema); err != nil {
+		return err
+	}
+	if err := validatePagination(schema); err != nil {
+		return err
+	}
+	if err := validateHidden(schema); err != nil {
+		return err
+	}
+	if err := validateIDStrategy(schema); err != nil {
+		return err
+	}
+	if err := validateRelationNames(schema); err != nil {
+		return err
+	}
+	for _, rel := range schema.Relations {
+		if err := rel.validateMorph(); err != nil {
+			return err
+		}
+		if err := rel.validateActions(); err != nil {
+			return err
+		}
+		if rel.Type != "belongs_to" || !rel.SetsNull() {
+			continue
+		}
+		if err := checkSetNull(schema, rel); err != nil {
+			return err
+		}
+	}
+	for _, col := range schema.Columns {
+		if col.SoftDelete && (!col.Nullable || col.BaseGoType() != "time.Time") {
+			return fmt.Errorf("la colonne %s doit être une date nullable pour la suppression logique", col.Name)
+		}
+	}
+	if err := validateScopes(schema); err != nil {
+		return err
+	}
+	if schema.Tree {
+		if err := validateTree(schema); err != nil {
+			return err
+		}
+	}
+	for _, col := range schema.Columns {
+		if !IsKnownType(col.Type) {
+			return fmt.Errorf("type inconnu '%s' pour la colonne %s", col.Type, col.Name)
+		}
+		if col.IsDecimal() {
+			if col.Precision < 0 || col.Scale < 0 || col.Precision > 65 {
+				return fmt.Errorf("précision invalide pour la colonne %s", col.Name)
+			}
+			if col.Scale > col.Precision {
+				return fmt.Errorf("l'échelle de la colonne %s dépasse sa précision", col.Name)
+			}
+		}
+	}
 	return nil
 }
 
+// expandMoneyColumns complète les colonnes money: précision par défaut
+// et ajout de la colonne de devise associée (<nom>_currency)
+func expandMoneyColumns(schema *Schema) {
+	var columns []Column
+	for _, col := range schema.Columns {
+		if col.Type != "money" {
+			columns = append(columns, col)
+			continue
+		}
+
+		if col.Precision == 0 {
+			col.Precision, col.Scale = 19, 4
+		}
+		if col.Currency == "" {
+			col.Currency = col.Name + "_currency"
+		}
+		columns = append(columns, col)
+
+		if schema.HasColumn(col.Currency) {
+			continue
+		}
+		columns = append(columns, Column{
+			Name:     col.Currency,
+			Type:     "currency",
+			Nullable: col.Nullable,
+			Comment:  fmt.Sprintf("Devise ISO 4217 de %s", col.Name),
+		})
+	}
+	schema.Columns = columns
+}
+
+// expandSoftDeletes ajoute la colonne deleted_at des schémas soft_deletes.
+// Une colonne deleted_at déclarée active également la suppression logique.
+func expandSoftDeletes(schema *Schema) {
+	if schema.SoftDeletes && !schema.HasColumn(SoftDeleteColumn) {
+		schema.Columns = append(schema.Columns, Column{
+			Name:     SoftDeleteColumn,
+			Type:     "timestamp",
+			Nullable: true,
+			Comment:  "Date de suppression (soft delete)",
+		})
+	}
+	for i := range schema.Columns {
+		if schema.Columns[i].Name == SoftDeleteColumn {
+			schema.SoftDeletes = true
+			schema.Columns[i].SoftDelete = true
+		}
+	}
+}
+
+// PrimaryKeys retourne les colonnes de la clé primaire (éventuellement composite).
+// Sans colonne marquée primary, la colonne "id" est utilisée.
+func (s *Schema) PrimaryKeys() []Column {
+	var keys []Column
+	for _, col := range s.Columns {
+		if col.Primary {
+			keys = append(keys, col)
+		}
+	}
+	if len(keys) == 0 {
+		for _, col := range s.Columns {
+			if col.Name == "id" {
+				keys = append(keys, col)
+			}
+		}
+	}
+	return keys
+}
+
+// IsPrimaryKey indique si une colonne fait partie de la clé primaire
+func (s *Schema) IsPrimaryKey(name string) bool {
+	for _, col := range s.PrimaryKeys() {
+		if col.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// HasColumn indique si le schéma déclare une colonne
+func (s *Schema) HasColumn(name string) bool {
+	for _, col := range s.Columns {
+		if col.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDecimal indique si la colonne contient un nombre décimal exact
+func (c *Column) IsDecimal() bool {
+	return c.Type == "decimal" || c.Type == "money"
+}
+
+// BaseGoType retourne le type Go de la colonne sans tenir compte de la nullabilité
+func (c *Column) BaseGoType() string {
+	if goType, exists := baseTypes[c.Type]; exists {
+		return goType
+	}
+	if def, ok := LookupType(c.Type); ok {
+		return def.GoType
+	}
+	return "interface{}"
+}
+
 // GetGoType convertit un type de base de données en type Go
 func (c *Column) GetGoType() string {
-	typeMap := map[string]string{
-		"string":    "string",
-		"text":      "string",
-		"int":       "int",
-		"integer":   "int",
-		"bigint":    "int64",
-		"smallint":  "int16",
-		"float":     "float64",
-		"double":    "float64",
-		"decimal":   "float64",
-		"boolean":   "bool",
-		"bool":      "bool",
-		"date":      "time.Time",
-		"datetime":  "time.Time",
-		"timestamp": "time.Time",
-		"time":      "time.Time",
-		"uuid":      "string",
-		"json":      "string",
-		"jsonb":     "string",
-	}
-
-	goType, exists := typeMap[c.Type]
-	if !exists {
-		goType = "interface{}"
-	}
-
-	if c.Nullable && goType != "interface{}" {
-		return "*" + goType
-	}
-
-	return goType
-}
-
-// GetDBType convertit le type en type de base de données
-func (c *Column) GetDBType() string {
-	if c.Type == "uuid" {
-		return "uuid"
+	if c.SoftDelete {
+		return "gorm.DeletedAt"
+	}
+	goType := c.BaseGoType()
+	if !c.Nullable || goType == "interface{}" {
+		return goType
+	}
+
+	switch nullableStyle {
+	case NullableSQL:
+		nullType, _ := sqlNullType(goType)
+		return nullType
+	case NullableGeneric:
+		return "types.Null[" + goType + "]"
+	}
+	return "*" + goType
+}
+
+// IsPointer indique si le type Go de la colonne est un pointeur
+func (c *Column) IsPointer() bool {
+	return strings.HasPrefix(c.GetGoType(), "*")
+}
+
+// NullFromPtr retourne l'expression Go convertissant un pointeur non nil
+// vers le type nullable de la colonne
+func (c *Column) NullFromPtr(ptr string) string {
+	switch nullableStyle {
+	case NullableSQL:
+		nullType, field := sqlNullType(c.BaseGoType())
+		return fmt.Sprintf("%s{%s: *%s, Valid: true}", nullType, field, ptr)
+	case NullableGeneric:
+		return fmt.Sprintf("types.NewNull(*%s)", ptr)
+	}
+	return ptr
+}
+
+// NullCheck retourne la condition Go vraie quand la valeur nullable expr est NULL
+func (c *Column) NullCheck(expr string) string {
+	if c.IsPointer() {
+		return expr + " == nil"
+	}
+	return "!" + expr + ".Valid"
+}
+
+// NullValue retourne l'expression Go de la valeur d'une valeur nullable expr non NULL
+func (c *Column) NullValue(expr string) string {
+	switch nullableStyle {
+	case NullableSQL:
+		_, field := sqlNullType(c.BaseGoType())
+		return expr + "." + field
+	case NullableGeneric:
+		return expr + ".V"
+	}
+	return "*" + expr
+}
+
+// NullZero retourne la valeur NULL du type nullable de la colonne
+func (c *Column) NullZero() string {
+	if c.IsPointer() {
+		return "nil"
+	}
+	return c.GetGoType() + "{}"
+}
+
+// IsZeroable indique si la valeur zéro du type est une valeur légitime
+// (false, 0) qui ne peut pas servir à détecter un champ absent
+func (c *Column) IsZeroable() bool {
+	switch c.BaseGoType() {
+	case "bool", "int", "int16", "int32", "int64", "float32", "float64", "decimal.Decimal":
+		return true
+	}
+	return false
+}
+
+// BaseImports retourne les imports nécessaires à BaseGoType
+func (c *Column) BaseImports() []string {
+	if def, ok := LookupType(c.Type); ok && def.Import != "" {
+		return []string{def.Import}
+	}
+	if imp := importFor(c.BaseGoType()); imp != "" {
+		return []string{imp}
+	}
+	return nil
+}
+
+// GetImports retourne les imports nécessaires au type Go de la colonne
+func (c *Column) GetImports() []string {
+	if c.SoftDelete {
+		return []string{"gorm.io/gorm"}
 	}
-	if c.Type == "string" && c.Size > 0 {
-		return fmt.Sprintf("varchar(%d)", c.Size)
+	goType := c.GetGoType()
+	if !c.Nullable || strings.HasPrefix(goType, "*") {
+		return c.BaseImports()
+	}
+
+	if nullableStyle == NullableGeneric {
+		return append(c.BaseImports(), "app/types")
+	}
+	if strings.HasPrefix(goType, "decimal.") {
+		return c.BaseImports()
+	}
+	if strings.HasPrefix(goType, "sql.Null[") {
+		return append(c.BaseImports(), "database/sql")
+	}
+	return []string{"database/sql"}
+}
+
+// GetDBType convertit le type en type de base de données pour un dialecte
+func (c *Column) GetDBType(dialect string) string {
+	if def, ok := LookupType(c.Type); ok {
+		if dbType := def.DBType(dialect); dbType != "" {
+			return dbType
+		}
+	}
+
+	switch c.Type {
+	case "string":
+		if dialect == DialectSQLite {
+			return "text"
+		}
+		if c.Size > 0 {
+			return fmt.Sprintf("varchar(%d)", c.Size)
+		}
+		return "varchar(255)"
+	case "uuid":
+		switch dialect {
+		case DialectMySQL:
+			return "char(36)"
+		case DialectSQLite:
+			return "text"
+		}
+		return "uuid"
+	case "int", "integer":
+		return "integer"
+	case "decimal", "money":
+		name := "numeric"
+		if dialect == DialectMySQL {
+			name = "decimal"
+		}
+		if c.Precision > 0 {
+			return fmt.Sprintf("%s(%d,%d)", name, c.Precision, c.Scale)
+		}
+		return name
+	case "float", "double":
+		if dialect == DialectPostgres {
+			return "double precision"
+		}
+		return "double"
+	case "boolean", "bool":
+		return "boolean"
+	case "datetime", "timestamp":
+		if dialect == DialectMySQL {
+			return "datetime"
+		}
+		return "timestamp"
+	case "jsonb":
+		if dialect == DialectPostgres {
+			return "jsonb"
+		}
+		return "json"
 	}
 	return c.Type
 }
@@ -137,32 +488,31 @@ func (c *Column) GetDBType() string {
 func (c *Column) GetValidationTag() string {
 	tags := []string{}
 
-	if !c.Nullable && c.Name != "id" && c.Name != "created_at" && c.Name != "updated_at" {
-		tags = append(tags, "required")
+	// Les types nullables non pointeurs (sql.Null*, types.Null) sont validés par les requests
+	if c.Nullable && !c.IsPointer() {
+		return ""
 	}
 
-	if c.Size > 0 {
-		tags = append(tags, fmt.Sprintf("max=%d", c.Size))
+	if c.Nullable {
+		tags = append(tags, "omitempty")
+	} else if !c.IsZeroable() && c.Name != "id" && c.Name != "created_at" && c.Name != "updated_at" {
+		// false et 0 sont des valeurs légitimes: "required" les rejetterait
+		tags = append(tags, "required")
 	}
 
-	if c.Unique {
-		tags = append(tags, "unique")
+	if def, ok := LookupType(c.Type); ok && def.Validation != "" {
+		tags = append(tags, def.Validation)
 	}
 
-	if len(tags) == 0 {
-		return ""
+	if c.Size > 0 {
+		tags = append(tags, fmt.Sprintf("max=%d", c.Size))
 	}
 
-	result := "validate:\""
-	for i, tag := range tags {
-		if i > 0 {
-			result += ","
-		}
-		result += tag
+	if len(tags) == 0 || (len(tags) == 1 && tags[0] == "omitempty") {
+		return ""
 	}
-	result += "\""
 
-	return result
+	return fmt.Sprintf("validate:\"%s\"", strings.Join(tags, ","))
 }
 
 // GetJSONTag retourne le tag JSON pour un champ
@@ -171,6 +521,9 @@ func (c *Column) GetJSONTag() string {
 	if c.Nullable {
 		tag += ",omitempty"
 	}
+	if def, ok := LookupType(c.Type); ok && def.JSON == "string" {
+		tag += ",string"
+	}
 	tag += "\""
 	return tag
 }
@@ -0,0 +1,195 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// parse écrit un schéma YAML dans un fichier temporaire et le parse
+func parse(t *testing.T, src string) (*Schema, error) {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "schema.yaml")
+	if err := os.WriteFile(file, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return ParseSchema(file)
+}
+
+// check vérifie l'erreur obtenue pour le cas name: aucune si want est vide, sinon une
+// erreur contenant want
+func check(t *testing.T, name string, err error, want string) {
+	t.Helper()
+	switch {
+	case want == "" && err != nil:
+		t.Errorf("%s: erreur inattendue: %v", name, err)
+	case want != "" && err == nil:
+		t.Errorf("%s: erreur attendue contenant %q", name, want)
+	case want != "" && !strings.Contains(err.Error(), want):
+		t.Errorf("%s: erreur = %q, want %q", name, err, want)
+	}
+}
+
+// checkErrors parse chaque schéma et vérifie l'erreur obtenue avec check
+func checkErrors(t *testing.T, tests []struct{ name, schema, err string }) {
+	t.Helper()
+	for _, tt := range tests {
+		_, err := parse(t, tt.schema)
+		check(t, tt.name, err, tt.err)
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	checkErrors(t, []struct{ name, schema, err string }{
+		{"valide", `
+table: posts
+model: Post
+columns: [{name: id, type: bigint}, {name: title, type: string}]`, ""},
+		{"sans table", `
+model: Post
+columns: [{name: id, type: bigint}]`, "nom de la table est requis"},
+		{"sans model", `
+table: posts
+columns: [{name: id, type: bigint}]`, "nom du model est requis"},
+		{"sans colonne", `
+table: posts
+model: Post`, "au moins une colonne"},
+		{"type inconnu", `
+table: posts
+model: Post
+columns: [{name: id, type: bigint}, {name: body, type: blob}]`, "type inconnu 'blob'"},
+		{"type personnalisé", `
+table: posts
+model: Post
+columns: [{name: id, type: bigint}, {name: contact, type: email}, {name: site, type: url}]`, ""},
+		{"échelle supérieure à la précision", `
+table: invoices
+model: Invoice
+columns: [{name: id, type: bigint}, {name: total, type: decimal, precision: 4, scale: 6}]`, "dépasse sa précision"},
+		{"précision trop grande", `
+table: invoices
+model: Invoice
+columns: [{name: id, type: bigint}, {name: total, type: decimal, precision: 70}]`, "précision invalide"},
+		{"sans clé primaire", `
+table: posts
+model: Post
+columns: [{name: title, type: string}]`, "aucune clé primaire"},
+		{"clé primaire nullable", `
+table: order_lines
+model: OrderLine
+columns: [{name: order_id, type: string, primary: true}, {name: line_no, type: integer, primary: true, nullable: true}]`, "ne peut pas être nullable"},
+		{"deleted_at non nullable", `
+table: posts
+model: Post
+columns: [{name: id, type: bigint}, {name: deleted_at, type: timestamp}]`, "date nullable pour la suppression logique"},
+	})
+}
+
+func TestMoneyColumns(t *testing.T) {
+	schema, err := parse(t, `
+table: invoices
+model: Invoice
+columns:
+  - {name: id, type: bigint}
+  - {name: total, type: money}
+  - {name: fee, type: money, nullable: true, currency: currency_code}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		typ       string
+		precision int
+		scale     int
+		nullable  bool
+	}{
+		{"total", "money", 19, 4, false},
+		{"total_currency", "currency", 0, 0, false},
+		{"fee", "money", 19, 4, true},
+		{"currency_code", "currency", 0, 0, true},
+	}
+	if len(schema.Columns) != 1+len(tests) {
+		t.Fatalf("colonnes = %d, want %d", len(schema.Columns), 1+len(tests))
+	}
+	for i, tt := range tests {
+		col := schema.Columns[i+1]
+		if col.Name != tt.name || col.Type != tt.typ || col.Precision != tt.precision || col.Scale != tt.scale || col.Nullable != tt.nullable {
+			t.Errorf("colonne %d = %s %s(%d,%d) nullable=%v, want %s %s(%d,%d) nullable=%v",
+				i+1, col.Name, col.Type, col.Precision, col.Scale, col.Nullable,
+				tt.name, tt.typ, tt.precision, tt.scale, tt.nullable)
+		}
+	}
+	if got := schema.Columns[1].GetGoType(); got != "decimal.Decimal" {
+		t.Errorf("GetGoType(total) = %q, want %q", got, "decimal.Decimal")
+	}
+}
+
+func TestPrimaryKeys(t *testing.T) {
+	tests := []struct {
+		schema string
+		want   []string
+	}{
+		{`
+table: posts
+model: Post
+columns: [{name: id, type: bigint}, {name: title, type: string}]`, []string{"id"}},
+		{`
+table: countries
+model: Country
+columns: [{name: code, type: string, size: 2, primary: true}, {name: id, type: bigint}]`, []string{"code"}},
+		{`
+table: order_lines
+model: OrderLine
+columns: [{name: order_id, type: string, primary: true}, {name: line_no, type: integer, primary: true}]`, []string{"order_id", "line_no"}},
+	}
+	for _, tt := range tests {
+		schema, err := parse(t, tt.schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, col := range schema.PrimaryKeys() {
+			got = append(got, col.Name)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("PrimaryKeys(%s) = %v, want %v", schema.Table, got, tt.want)
+		}
+	}
+}
+
+func TestSoftDeletes(t *testing.T) {
+	tests := []struct {
+		schema string
+		want   bool
+	}{
+		{`
+table: posts
+model: Post
+soft_deletes: true
+columns: [{name: id, type: bigint}]`, true},
+		{`
+table: posts
+model: Post
+columns: [{name: id, type: bigint}, {name: deleted_at, type: timestamp, nullable: true}]`, true},
+		{`
+table: posts
+model: Post
+columns: [{name: id, type: bigint}]`, false},
+	}
+	for _, tt := range tests {
+		schema, err := parse(t, tt.schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if schema.SoftDeletes != tt.want {
+			t.Errorf("SoftDeletes = %v, want %v", schema.SoftDeletes, tt.want)
+		}
+		for _, col := range schema.Columns {
+			if col.Name == SoftDeleteColumn && col.GetGoType() != "gorm.DeletedAt" {
+				t.Errorf("GetGoType(deleted_at) = %q, want gorm.DeletedAt", col.GetGoType())
+			}
+		}
+	}
+}
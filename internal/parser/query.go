@@ -0,0 +1,69 @@
+package parser
+
+import "fmt"
+
+// Familles de colonnes filtrables, qui déterminent la conversion des valeurs de
+// ?filter[...] et les opérateurs admis
+const (
+	FilterString  = "string"
+	FilterInteger = "integer"
+	FilterDecimal = "decimal"
+	FilterBoolean = "boolean"
+	FilterTime    = "time"
+)
+
+// FilterKind retourne la famille de filtre d'une colonne, ou "" si la colonne ne peut
+// être ni filtrée ni triée (json)
+func (c *Column) FilterKind() string {
+	if c.Type == "json" || c.Type == "jsonb" {
+		return ""
+	}
+	if c.Type == "time" {
+		return FilterString
+	}
+	switch c.BaseGoType() {
+	case "string":
+		return FilterString
+	case "int", "int16", "int32", "int64":
+		return FilterInteger
+	case "float64", "decimal.Decimal":
+		return FilterDecimal
+	case "bool":
+		return FilterBoolean
+	case "time.Time":
+		return FilterTime
+	}
+	return ""
+}
+
+// FilterableColumns retourne les colonnes filterable: true
+func (s *Schema) FilterableColumns() []Column {
+	var columns []Column
+	for _, col := range s.Columns {
+		if col.Filterable {
+			columns = append(columns, col)
+		}
+	}
+	return columns
+}
+
+// SortableColumns retourne les colonnes sortable: true
+func (s *Schema) SortableColumns() []Column {
+	var columns []Column
+	for _, col := range s.Columns {
+		if col.Sortable {
+			columns = append(columns, col)
+		}
+	}
+	return columns
+}
+
+// validateQueryColumns vérifie que les colonnes filtrables ou triables ont un type comparable
+func validateQueryColumns(schema *Schema) error {
+	for _, col := range schema.Columns {
+		if (col.Filterable || col.Sortable) && col.FilterKind() == "" {
+			return fmt.Errorf("la colonne %s de type %s ne peut pas être filterable ou sortable", col.Name, col.Type)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package parser
+
+import "testing"
+
+func TestFilterKind(t *testing.T) {
+	tests := []struct {
+		typ  string
+		want string
+	}{
+		{"string", FilterString},
+		{"uuid", FilterString},
+		{"email", FilterString},
+		{"time", FilterString},
+		{"integer", FilterInteger},
+		{"smallint", FilterInteger},
+		{"bigint", FilterInteger},
+		{"decimal", FilterDecimal},
+		{"money", FilterDecimal},
+		{"float", FilterDecimal},
+		{"boolean", FilterBoolean},
+		{"timestamp", FilterTime},
+		{"date", FilterTime},
+		{"json", ""},
+		{"jsonb", ""},
+	}
+	for _, tt := range tests {
+		col := Column{Type: tt.typ}
+		if got := col.FilterKind(); got != tt.want {
+			t.Errorf("FilterKind(%s) = %q, want %q", tt.typ, got, tt.want)
+		}
+	}
+}
+
+func TestValidateQueryColumns(t *testing.T) {
+	checkErrors(t, []struct{ name, schema, err string }{
+		{"colonnes comparables", `
+table: articles
+model: Article
+columns:
+  - {name: id, type: bigint, sortable: true}
+  - {name: status, type: string, filterable: true}
+  - {name: price, type: decimal, filterable: true, sortable: true}
+  - {name: published_at, type: timestamp, nullable: true, filterable: true, sortable: true}`, ""},
+		{"json filterable", `
+table: articles
+model: Article
+columns: [{name: id, type: bigint}, {name: meta, type: json, filterable: true}]`, "ne peut pas être filterable ou sortable"},
+		{"jsonb sortable", `
+table: articles
+model: Article
+columns: [{name: id, type: bigint}, {name: meta, type: jsonb, sortable: true}]`, "ne peut pas être filterable ou sortable"},
+	})
+}
@@ -0,0 +1,385 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Actions référentielles supportées par on_delete et on_update
+var referentialActions = map[string]string{
+	"cascade":   "CASCADE",
+	"restrict":  "RESTRICT",
+	"set null":  "SET NULL",
+	"no action": "NO ACTION",
+}
+
+// normalizeAction convertit une action du schéma (ex: "set_null") en SQL (ex: "SET NULL")
+func normalizeAction(action string) (string, bool) {
+	key := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(action), "_", " "))
+	sql, ok := referentialActions[key]
+	return sql, ok
+}
+
+// OnDeleteSQL retourne l'action ON DELETE au format SQL ("" si non définie)
+func (r *Relation) OnDeleteSQL() string {
+	sql, _ := normalizeAction(r.OnDelete)
+	return sql
+}
+
+// OnUpdateSQL retourne l'action ON UPDATE au format SQL ("" si non définie)
+func (r *Relation) OnUpdateSQL() string {
+	sql, _ := normalizeAction(r.OnUpdate)
+	return sql
+}
+
+// SetsNull indique si la relation remet la clé étrangère à NULL
+func (r *Relation) SetsNull() bool {
+	return r.OnDeleteSQL() == "SET NULL" || r.OnUpdateSQL() == "SET NULL"
+}
+
+// Constraint retourne la valeur du tag GORM constraint (ex: "OnDelete:CASCADE,OnUpdate:RESTRICT")
+func (r *Relation) Constraint() string {
+	var parts []string
+	if action := r.OnDeleteSQL(); action != "" {
+		parts = append(parts, "OnDelete:"+action)
+	}
+	if action := r.OnUpdateSQL(); action != "" {
+		parts = append(parts, "OnUpdate:"+action)
+	}
+	return strings.Join(parts, ",")
+}
+
+// validateActions vérifie les actions référentielles de la relation
+func (r *Relation) validateActions() error {
+	for _, field := range [][2]string{{"on_delete", r.OnDelete}, {"on_update", r.OnUpdate}} {
+		name, action := field[0], field[1]
+		if action == "" {
+			continue
+		}
+		if r.Type == "many_to_many" {
+			return fmt.Errorf("%s n'est pas supporté sur la relation many_to_many vers %s", name, r.Model)
+		}
+		if r.IsMorph() {
+			return fmt.Errorf("%s n'est pas supporté sur la relation polymorphe %s", name, r.Name)
+		}
+		if _, ok := normalizeAction(action); !ok {
+			return fmt.Errorf("action %s inconnue pour la relation vers %s: %s (cascade, restrict, set null, no action)", name, r.Model, action)
+		}
+	}
+	return nil
+}
+
+// IsMorph indique si la relation est polymorphe (morph_to, morph_many, morph_one)
+func (r *Relation) IsMorph() bool {
+	switch r.Type {
+	case "morph_to", "morph_many", "morph_one":
+		return true
+	}
+	return false
+}
+
+// MorphTypeColumn retourne la colonne discriminante d'une relation polymorphe (<nom>_type)
+func (r *Relation) MorphTypeColumn() string {
+	return r.Name + "_type"
+}
+
+// MorphIDColumn retourne la colonne d'identifiant d'une relation polymorphe (<nom>_id)
+func (r *Relation) MorphIDColumn() string {
+	return r.Name + "_id"
+}
+
+// validateMorph vérifie la déclaration d'une relation polymorphe
+func (r *Relation) validateMorph() error {
+	switch r.Type {
+	case "morph_to":
+		if r.Name == "" {
+			return fmt.Errorf("la relation morph_to doit définir name")
+		}
+		if len(r.Models) == 0 {
+			return fmt.Errorf("la relation morph_to %s doit lister les models autorisés (models)", r.Name)
+		}
+		if r.Preload {
+			return fmt.Errorf("la relation morph_to %s ne peut pas être préchargée (preload)", r.Name)
+		}
+	case "morph_many", "morph_one":
+		if r.Model == "" || r.Name == "" {
+			return fmt.Errorf("la relation %s doit définir model et name", r.Type)
+		}
+	}
+	return nil
+}
+
+// expandMorphColumns ajoute les colonnes <nom>_type et <nom>_id des relations morph_to,
+// la validation du discriminant et l'index associé. Les colonnes déjà déclarées sont
+// conservées; la colonne <nom>_id ajoutée est bigint jusqu'à ce que ResolveMorphColumns
+// lui donne le type de la clé primaire des models autorisés.
+func expandMorphColumns(schema *Schema) {
+	for _, rel := range schema.Relations {
+		if rel.Type != "morph_to" || rel.Name == "" {
+			continue
+		}
+
+		typeColumn, idColumn := rel.MorphTypeColumn(), rel.MorphIDColumn()
+		if !schema.HasColumn(typeColumn) {
+			schema.Columns = append(schema.Columns, Column{
+				Name:    typeColumn,
+				Type:    "string",
+				Size:    255,
+				Comment: fmt.Sprintf("Model parent de la relation %s", rel.Name),
+			})
+		}
+		if !schema.HasColumn(idColumn) {
+			schema.Columns = append(schema.Columns, Column{
+				Name:    idColumn,
+				Type:    "bigint",
+				Comment: fmt.Sprintf("Identifiant du parent de la relation %s", rel.Name),
+				Morph:   true,
+			})
+		}
+
+		hasValidation := false
+		for _, val := range schema.Validations {
+			if val.Field == typeColumn {
+				hasValidation = true
+			}
+		}
+		if !hasValidation {
+			models := make([]interface{}, 0, len(rel.Models))
+			for _, model := range rel.Models {
+				models = append(models, model)
+			}
+			schema.Validations = append(schema.Validations, Validation{
+				Field: typeColumn,
+				Rules: map[string]interface{}{"required": true, "in": models},
+			})
+		}
+
+		schema.Indexes = append(schema.Indexes, Index{
+			Name:    fmt.Sprintf("idx_%s_%s", schema.Table, rel.Name),
+			Columns: []string{typeColumn, idColumn},
+		})
+	}
+}
+
+// morphKey retourne la clé primaire des models autorisés par une relation morph_to, dont
+// la colonne <nom>_id reprend le type: ces models doivent avoir une clé simple du même
+// type (la taille retenue est la plus grande). ok est faux si aucun model n'est connu.
+func morphKey(schema *Schema, rel Relation, byModel map[string]*Schema) (key Column, ok bool, err error) {
+	var first string
+	for _, model := range rel.Models {
+		parent, known := byModel[model]
+		if !known {
+			continue
+		}
+		keys := parent.PrimaryKeys()
+		if len(keys) != 1 {
+			return Column{}, false, fmt.Errorf("%s: %s a une clé primaire composite et ne peut pas être le parent de la relation morph_to %s",
+				schema.Table, model, rel.Name)
+		}
+		if !ok {
+			key, ok, first = keys[0], true, model
+			continue
+		}
+		if keys[0].Type != key.Type {
+			return Column{}, false, fmt.Errorf("%s: les models de la relation morph_to %s ont des clés primaires de types différents (%s: %s, %s: %s)",
+				schema.Table, rel.Name, first, key.Type, model, keys[0].Type)
+		}
+		if keys[0].Size > key.Size {
+			key.Size = keys[0].Size
+		}
+	}
+	return key, ok, nil
+}
+
+// ResolveMorphColumns donne aux colonnes <nom>_id ajoutées pour les relations morph_to de
+// schema le type de la clé primaire des models autorisés, lus dans schemas. Les relations
+// dont les models sont incohérents sont signalées par ValidateSchemas.
+func ResolveMorphColumns(schema *Schema, schemas []*Schema) {
+	byModel := make(map[string]*Schema, len(schemas))
+	for _, s := range schemas {
+		byModel[s.Model] = s
+	}
+	for _, rel := range schema.Relations {
+		if rel.Type != "morph_to" {
+			continue
+		}
+		key, ok, err := morphKey(schema, rel, byModel)
+		if err != nil || !ok {
+			continue
+		}
+		for i, col := range schema.Columns {
+			if col.Name == rel.MorphIDColumn() && col.Morph {
+				schema.Columns[i].Type = key.Type
+				schema.Columns[i].Size = key.Size
+				schema.Columns[i].Precision = key.Precision
+				schema.Columns[i].Scale = key.Scale
+			}
+		}
+	}
+}
+
+// morphTo retourne la relation morph_to d'un schéma portant le nom donné
+func (s *Schema) morphTo(name string) (Relation, bool) {
+	for _, rel := range s.Relations {
+		if rel.Type == "morph_to" && rel.Name == name {
+			return rel, true
+		}
+	}
+	return Relation{}, false
+}
+
+// IsSelfReferencing indique si la relation pointe vers le model qui la déclare
+func (r *Relation) IsSelfReferencing(s *Schema) bool {
+	return !r.IsMorph() && r.Model == s.Model
+}
+
+// TreeRelation retourne la relation belongs_to vers le même model qui porte la hiérarchie
+func (s *Schema) TreeRelation() (Relation, bool) {
+	for _, rel := range s.Relations {
+		if rel.Type == "belongs_to" && rel.IsSelfReferencing(s) {
+			return rel, true
+		}
+	}
+	return Relation{}, false
+}
+
+// validateTree vérifie qu'un schéma tree: true déclare sa relation parente
+func validateTree(schema *Schema) error {
+	rel, ok := schema.TreeRelation()
+	if !ok {
+		return fmt.Errorf("tree: true nécessite une relation belongs_to vers %s (ex: name: parent)", schema.Model)
+	}
+	if len(schema.PrimaryKeys()) != 1 {
+		return fmt.Errorf("tree: true n'est pas supporté avec une clé primaire composite")
+	}
+	for _, col := range schema.Columns {
+		if col.Name == rel.ForeignKey {
+			if !col.Nullable {
+				return fmt.Errorf("la colonne %s doit être nullable pour représenter les racines de l'arbre", col.Name)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("colonne de clé étrangère %s introuvable pour l'arbre %s", rel.ForeignKey, schema.Model)
+}
+
+// validateRelationNames vérifie que chaque relation produit un champ distinct:
+// un model référencé plusieurs fois doit être distingué par name
+func validateRelationNames(schema *Schema) error {
+	names := map[string]bool{}
+	targets := map[string]int{}
+	for _, rel := range schema.Relations {
+		if rel.IsMorph() {
+			continue
+		}
+		targets[rel.Model]++
+		if rel.Name == "" {
+			continue
+		}
+		if names[rel.Name] {
+			return fmt.Errorf("nom de relation dupliqué: %s", rel.Name)
+		}
+		if schema.HasColumn(rel.Name) {
+			return fmt.Errorf("la relation %s porte le nom d'une colonne", rel.Name)
+		}
+		names[rel.Name] = true
+	}
+	for _, rel := range schema.Relations {
+		if !rel.IsMorph() && rel.Name == "" && targets[rel.Model] > 1 {
+			return fmt.Errorf("%s est référencé par plusieurs relations: précisez name sur chacune (ex: author, reviewer)", rel.Model)
+		}
+	}
+	return nil
+}
+
+// checkSetNull vérifie que la clé étrangère d'une relation "set null" est nullable.
+// owner est le schéma qui porte la colonne de clé étrangère.
+func checkSetNull(owner *Schema, rel Relation) error {
+	for _, col := range owner.Columns {
+		if col.Name != rel.ForeignKey {
+			continue
+		}
+		if !col.Nullable {
+			return fmt.Errorf("%s: la relation vers %s utilise \"set null\" mais la colonne %s n'est pas nullable",
+				owner.Table, rel.Model, col.Name)
+		}
+		return nil
+	}
+	return fmt.Errorf("%s: colonne de clé étrangère %s introuvable pour la relation vers %s",
+		owner.Table, rel.ForeignKey, rel.Model)
+}
+
+// checkMorph vérifie qu'une relation morph_many/morph_one correspond à une relation
+// morph_to du schéma cible qui autorise le model parent
+func checkMorph(parent *Schema, rel Relation, target *Schema) error {
+	if target == nil {
+		return nil
+	}
+	morph, ok := target.morphTo(rel.Name)
+	if !ok {
+		return fmt.Errorf("%s: la relation %s vers %s n'a pas de morph_to %s correspondante",
+			parent.Table, rel.Type, rel.Model, rel.Name)
+	}
+	for _, model := range morph.Models {
+		if model == parent.Model {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: %s n'est pas un model autorisé par la relation morph_to %s de %s",
+		parent.Table, parent.Model, rel.Name, rel.Model)
+}
+
+// ValidateSchemas effectue les vérifications croisées entre schémas
+// (clés étrangères portées par le schéma cible des relations has_many et has_one,
+// models autorisés des relations polymorphes et type de leur clé primaire)
+func ValidateSchemas(schemas []*Schema) []error {
+	byModel := make(map[string]*Schema, len(schemas))
+	for _, schema := range schemas {
+		byModel[schema.Model] = schema
+	}
+
+	var errs []error
+	for _, schema := range schemas {
+		for _, rel := range schema.Relations {
+			if rel.Type == "morph_to" {
+				for _, model := range rel.Models {
+					if _, ok := byModel[model]; !ok {
+						errs = append(errs, fmt.Errorf("%s: model %s inconnu dans la relation morph_to %s",
+							schema.Table, model, rel.Name))
+					}
+				}
+				key, ok, err := morphKey(schema, rel, byModel)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				for _, col := range schema.Columns {
+					if ok && col.Name == rel.MorphIDColumn() && !col.Morph && col.Type != key.Type {
+						errs = append(errs, fmt.Errorf("%s: la colonne %s est %s mais la clé primaire des models de la relation morph_to %s est %s",
+							schema.Table, col.Name, col.Type, rel.Name, key.Type))
+					}
+				}
+				continue
+			}
+			if rel.Type == "morph_many" || rel.Type == "morph_one" {
+				if err := checkMorph(schema, rel, byModel[rel.Model]); err != nil {
+					errs = append(errs, err)
+				}
+				continue
+			}
+			if rel.Type != "has_many" && rel.Type != "has_one" {
+				continue
+			}
+			target, ok := byModel[rel.Model]
+			if !ok {
+				continue
+			}
+			if rel.SetsNull() {
+				if err := checkSetNull(target, rel); err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", schema.Table, err))
+				}
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,254 @@
+package parser
+
+import "testing"
+
+func TestValidateRelations(t *testing.T) {
+	checkErrors(t, []struct{ name, schema, err string }{
+		{"actions valides", `
+table: posts
+model: Post
+columns: [{name: id, type: bigint}, {name: user_id, type: bigint, nullable: true}]
+relations: [{type: belongs_to, model: User, foreign_key: user_id, on_delete: set_null, on_update: CASCADE}]`, ""},
+		{"action inconnue", `
+table: posts
+model: Post
+columns: [{name: id, type: bigint}, {name: user_id, type: bigint}]
+relations: [{type: belongs_to, model: User, foreign_key: user_id, on_delete: nullify}]`, "action on_delete inconnue"},
+		{"action sur many_to_many", `
+table: posts
+model: Post
+columns: [{name: id, type: bigint}]
+relations: [{type: many_to_many, model: Tag, pivot_table: post_tags, on_delete: cascade}]`, "n'est pas supporté sur la relation many_to_many"},
+		{"set null sur une clé non nullable", `
+table: posts
+model: Post
+columns: [{name: id, type: bigint}, {name: user_id, type: bigint}]
+relations: [{type: belongs_to, model: User, foreign_key: user_id, on_delete: set null}]`, "n'est pas nullable"},
+		{"clé étrangère introuvable", `
+table: posts
+model: Post
+columns: [{name: id, type: bigint}]
+relations: [{type: belongs_to, model: User, foreign_key: user_id, on_delete: set null}]`, "introuvable"},
+		{"morph_to sans name", `
+table: comments
+model: Comment
+columns: [{name: id, type: bigint}]
+relations: [{type: morph_to, models: [Post]}]`, "doit définir name"},
+		{"morph_to sans models", `
+table: comments
+model: Comment
+columns: [{name: id, type: bigint}]
+relations: [{type: morph_to, name: commentable}]`, "doit lister les models autorisés"},
+		{"morph_many sans name", `
+table: posts
+model: Post
+columns: [{name: id, type: bigint}]
+relations: [{type: morph_many, model: Comment}]`, "doit définir model et name"},
+		{"action sur une relation polymorphe", `
+table: posts
+model: Post
+columns: [{name: id, type: bigint}]
+relations: [{type: morph_many, model: Comment, name: commentable, on_delete: cascade}]`, "relation polymorphe"},
+		{"arbre", `
+table: categories
+model: Category
+tree: true
+columns: [{name: id, type: bigint}, {name: parent_id, type: bigint, nullable: true}]
+relations:
+  - {type: belongs_to, model: Category, name: parent, foreign_key: parent_id}
+  - {type: has_many, model: Category, name: children, foreign_key: parent_id}`, ""},
+		{"arbre sans relation parente", `
+table: categories
+model: Category
+tree: true
+columns: [{name: id, type: bigint}, {name: parent_id, type: bigint, nullable: true}]`, "nécessite une relation belongs_to"},
+		{"arbre à racine impossible", `
+table: categories
+model: Category
+tree: true
+columns: [{name: id, type: bigint}, {name: parent_id, type: bigint}]
+relations: [{type: belongs_to, model: Category, name: parent, foreign_key: parent_id}]`, "doit être nullable pour représenter les racines"},
+		{"model référencé deux fois sans name", `
+table: posts
+model: Post
+columns: [{name: id, type: bigint}, {name: author_id, type: bigint}, {name: reviewer_id, type: bigint}]
+relations:
+  - {type: belongs_to, model: User, foreign_key: author_id}
+  - {type: belongs_to, model: User, foreign_key: reviewer_id}`, "précisez name"},
+		{"name dupliqué", `
+table: posts
+model: Post
+columns: [{name: id, type: bigint}, {name: author_id, type: bigint}, {name: reviewer_id, type: bigint}]
+relations:
+  - {type: belongs_to, model: User, name: author, foreign_key: author_id}
+  - {type: belongs_to, model: User, name: author, foreign_key: reviewer_id}`, "nom de relation dupliqué"},
+		{"name d'une colonne", `
+table: posts
+model: Post
+columns: [{name: id, type: bigint}, {name: author, type: string}, {name: author_id, type: bigint}]
+relations: [{type: belongs_to, model: User, name: author, foreign_key: author_id}]`, "porte le nom d'une colonne"},
+	})
+}
+
+func TestValidateSchemas(t *testing.T) {
+	tests := []struct {
+		name    string
+		schemas []string
+		err     string
+	}{
+		{"has_many set null valide", []string{`
+table: users
+model: User
+columns: [{name: id, type: bigint}]
+relations: [{type: has_many, model: Post, foreign_key: user_id, on_delete: set null}]`, `
+table: posts
+model: Post
+columns: [{name: id, type: bigint}, {name: user_id, type: bigint, nullable: true}]`}, ""},
+		{"has_many set null sur une clé non nullable", []string{`
+table: users
+model: User
+columns: [{name: id, type: bigint}]
+relations: [{type: has_many, model: Post, foreign_key: user_id, on_delete: set null}]`, `
+table: posts
+model: Post
+columns: [{name: id, type: bigint}, {name: user_id, type: bigint}]`}, "users: posts: la relation vers Post utilise \"set null\""},
+		{"morph_to vers un model inconnu", []string{`
+table: comments
+model: Comment
+columns: [{name: id, type: bigint}]
+relations: [{type: morph_to, name: commentable, models: [Post, Video]}]`, `
+table: posts
+model: Post
+columns: [{name: id, type: bigint}]`}, "model Video inconnu"},
+		{"morph_many sans morph_to", []string{`
+table: posts
+model: Post
+columns: [{name: id, type: bigint}]
+relations: [{type: morph_many, model: Comment, name: commentable}]`, `
+table: comments
+model: Comment
+columns: [{name: id, type: bigint}]`}, "n'a pas de morph_to commentable"},
+		{"morph_many des models autorisés", []string{`
+table: videos
+model: Video
+columns: [{name: id, type: bigint}]
+relations: [{type: morph_many, model: Comment, name: commentable}]`, `
+table: comments
+model: Comment
+columns: [{name: id, type: bigint}]
+relations: [{type: morph_to, name: commentable, models: [Video, Post]}]`, `
+table: posts
+model: Post
+columns: [{name: id, type: bigint}]
+relations: [{type: morph_many, model: Comment, name: commentable}]`}, ""},
+		{"morph_many d'un model non autorisé", []string{`
+table: posts
+model: Post
+columns: [{name: id, type: bigint}]
+relations: [{type: morph_many, model: Comment, name: commentable}]`, `
+table: comments
+model: Comment
+columns: [{name: id, type: bigint}]
+relations: [{type: morph_to, name: commentable, models: [Video]}]`, `
+table: videos
+model: Video
+columns: [{name: id, type: bigint}]`}, "Post n'est pas un model autorisé"},
+		{"morph_to vers des clés de types différents", []string{`
+table: comments
+model: Comment
+columns: [{name: id, type: bigint}]
+relations: [{type: morph_to, name: commentable, models: [Post, Video]}]`, `
+table: posts
+model: Post
+columns: [{name: id, type: bigint}]`, `
+table: videos
+model: Video
+columns: [{name: id, type: uuid, primary: true}]`}, "clés primaires de types différents"},
+		{"morph_to vers une clé composite", []string{`
+table: comments
+model: Comment
+columns: [{name: id, type: bigint}]
+relations: [{type: morph_to, name: commentable, models: [OrderLine]}]`, `
+table: order_lines
+model: OrderLine
+columns: [{name: order_id, type: string, primary: true}, {name: line_no, type: integer, primary: true}]`}, "clé primaire composite"},
+		{"colonne morph déclarée d'un autre type", []string{`
+table: comments
+model: Comment
+columns: [{name: id, type: bigint}, {name: commentable_id, type: bigint}]
+relations: [{type: morph_to, name: commentable, models: [Post]}]`, `
+table: posts
+model: Post
+columns: [{name: id, type: uuid, primary: true}]`}, "la colonne commentable_id est bigint"},
+	}
+	for _, tt := range tests {
+		var schemas []*Schema
+		for _, src := range tt.schemas {
+			schema, err := parse(t, src)
+			if err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			schemas = append(schemas, schema)
+		}
+		var err error
+		if errs := ValidateSchemas(schemas); len(errs) > 0 {
+			err = errs[0]
+		}
+		check(t, tt.name, err, tt.err)
+	}
+}
+
+func TestMorphColumns(t *testing.T) {
+	comment, err := parse(t, `
+table: comments
+model: Comment
+columns: [{name: id, type: bigint}]
+relations: [{type: morph_to, name: commentable, models: [Post, Video]}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	post, err := parse(t, `
+table: posts
+model: Post
+columns: [{name: id, type: uuid, primary: true}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	video, err := parse(t, `
+table: videos
+model: Video
+columns: [{name: id, type: uuid, primary: true}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	columns := map[string]Column{}
+	for _, col := range comment.Columns {
+		columns[col.Name] = col
+	}
+	if col := columns["commentable_type"]; col.Type != "string" {
+		t.Errorf("commentable_type = %q, want string", col.Type)
+	}
+	if col := columns["commentable_id"]; col.Type != "bigint" || !col.Morph {
+		t.Errorf("commentable_id avant résolution = %q (morph=%v), want bigint", col.Type, col.Morph)
+	}
+	var rules map[string]interface{}
+	for _, val := range comment.Validations {
+		if val.Field == "commentable_type" {
+			rules = val.Rules
+		}
+	}
+	if in, _ := rules["in"].([]interface{}); len(in) != 2 || in[0] != "Post" || in[1] != "Video" {
+		t.Errorf("validation de commentable_type = %v, want in [Post Video]", rules)
+	}
+
+	ResolveMorphColumns(comment, []*Schema{comment, post, video})
+	for _, col := range comment.Columns {
+		if col.Name == "commentable_id" && col.Type != "uuid" {
+			t.Errorf("commentable_id après résolution = %q, want uuid", col.Type)
+		}
+	}
+	if errs := ValidateSchemas([]*Schema{comment, post, video}); len(errs) > 0 {
+		t.Errorf("ValidateSchemas: %v", errs)
+	}
+}
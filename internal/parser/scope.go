@@ -0,0 +1,166 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"go-scaffold/internal/inflection"
+)
+
+// Scope représente une condition de requête réutilisable (ex: published)
+type Scope struct {
+	Name       string           `yaml:"name"`
+	Conditions []ScopeCondition `yaml:"conditions"`
+	Parameters []ScopeParameter `yaml:"parameters"`
+}
+
+// ScopeCondition représente une condition d'un scope: colonne comparée à une valeur
+// fixe ou à un paramètre, ou fragment SQL brut
+type ScopeCondition struct {
+	Column   string      `yaml:"column"`
+	Operator string      `yaml:"operator"` // =, !=, <, <=, >, >=, like, in, is null, is not null
+	Value    interface{} `yaml:"value"`
+	Param    string      `yaml:"param"`
+	SQL      string      `yaml:"sql"`
+}
+
+// ScopeParameter représente un paramètre d'un scope
+type ScopeParameter struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+}
+
+// scopeOperators associe les opérateurs supportés à leur forme SQL
+var scopeOperators = map[string]string{
+	"=":           "=",
+	"!=":          "<>",
+	"<":           "<",
+	"<=":          "<=",
+	">":           ">",
+	">=":          ">=",
+	"like":        "LIKE",
+	"in":          "IN",
+	"is null":     "IS NULL",
+	"is not null": "IS NOT NULL",
+}
+
+// reservedScopeNames contient les méthodes du repository qu'un scope ne peut pas masquer,
+// y compris celles générées selon les options du schéma ou du projet; les lectures
+// (FindByID, FindTrashed, Find<Parent> des routes imbriquées...) sont réservées par le
+// préfixe Find
+var reservedScopeNames = map[string]bool{
+	"Create": true, "Update": true, "Delete": true,
+	"Restore": true, "ForceDelete": true, "Ancestors": true, "Descendants": true, "Search": true,
+	"History": true, "WithContext": true,
+}
+
+// reservedScopeParameters contient les noms déjà utilisés par les méthodes générées
+var reservedScopeParameters = map[string]bool{"page": true, "page_size": true, "cursor": true, "db": true, "ctx": true}
+
+// HasParameters indique si le scope attend des paramètres
+func (s *Scope) HasParameters() bool {
+	return len(s.Parameters) > 0
+}
+
+// GoType retourne le type Go d'un paramètre de scope
+func (p *ScopeParameter) GoType() string {
+	col := Column{Type: p.Type}
+	return col.BaseGoType()
+}
+
+// Imports retourne les imports nécessaires au type Go du paramètre
+func (p *ScopeParameter) Imports() []string {
+	col := Column{Type: p.Type}
+	return col.BaseImports()
+}
+
+// Where retourne la clause SQL de la condition, avec un ? pour la valeur éventuelle
+func (c *ScopeCondition) Where() string {
+	if c.SQL != "" {
+		return c.SQL
+	}
+	op := scopeOperators[c.normalizedOperator()]
+	if c.IsUnary() {
+		return c.Column + " " + op
+	}
+	return c.Column + " " + op + " ?"
+}
+
+// IsUnary indique si la condition ne compare la colonne à aucune valeur (is null, sql brut)
+func (c *ScopeCondition) IsUnary() bool {
+	if c.SQL != "" {
+		return true
+	}
+	op := c.normalizedOperator()
+	return op == "is null" || op == "is not null"
+}
+
+func (c *ScopeCondition) normalizedOperator() string {
+	if c.Operator == "" {
+		return "="
+	}
+	return strings.ToLower(strings.Join(strings.Fields(c.Operator), " "))
+}
+
+// validateScopes vérifie les scopes d'un schéma
+func validateScopes(schema *Schema) error {
+	names := map[string]bool{}
+	for _, scope := range schema.Scopes {
+		if scope.Name == "" {
+			return fmt.Errorf("chaque scope doit définir name")
+		}
+		if names[scope.Name] {
+			return fmt.Errorf("scope dupliqué: %s", scope.Name)
+		}
+		names[scope.Name] = true
+		if method := inflection.Pascal(scope.Name); reservedScopeNames[method] || strings.HasPrefix(method, "Find") {
+			return fmt.Errorf("le nom de scope %s est réservé aux méthodes du repository", scope.Name)
+		}
+		if len(scope.Conditions) == 0 {
+			return fmt.Errorf("le scope %s doit définir au moins une condition", scope.Name)
+		}
+
+		params := map[string]bool{}
+		for _, param := range scope.Parameters {
+			if param.Name == "" || !IsKnownType(param.Type) {
+				return fmt.Errorf("paramètre invalide dans le scope %s: %s (%s)", scope.Name, param.Name, param.Type)
+			}
+			if reservedScopeParameters[param.Name] || params[param.Name] {
+				return fmt.Errorf("nom de paramètre réservé ou dupliqué dans le scope %s: %s", scope.Name, param.Name)
+			}
+			params[param.Name] = true
+		}
+
+		for _, cond := range scope.Conditions {
+			if cond.SQL != "" {
+				continue
+			}
+			if !schema.HasColumn(cond.Column) {
+				return fmt.Errorf("colonne inconnue dans le scope %s: %s", scope.Name, cond.Column)
+			}
+			op := cond.normalizedOperator()
+			if _, ok := scopeOperators[op]; !ok {
+				return fmt.Errorf("opérateur inconnu dans le scope %s: %s", scope.Name, cond.Operator)
+			}
+			if cond.IsUnary() {
+				continue
+			}
+			if cond.Param != "" {
+				if !params[cond.Param] {
+					return fmt.Errorf("le scope %s utilise le paramètre non déclaré %s", scope.Name, cond.Param)
+				}
+				if op == "in" {
+					return fmt.Errorf("l'opérateur in du scope %s attend une liste de valeurs, pas un paramètre", scope.Name)
+				}
+				continue
+			}
+			if cond.Value == nil {
+				return fmt.Errorf("la condition sur %s du scope %s doit définir value ou param", cond.Column, scope.Name)
+			}
+			if _, isList := cond.Value.([]interface{}); isList != (op == "in") {
+				return fmt.Errorf("la condition sur %s du scope %s: une liste de valeurs n'est acceptée qu'avec in", cond.Column, scope.Name)
+			}
+		}
+	}
+	return nil
+}
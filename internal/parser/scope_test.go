@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"fmt"
+	"testing"
+)
+
+// scopeSchema retourne un schéma d'articles déclarant les scopes donnés (YAML en flux)
+func scopeSchema(scopes string) string {
+	return fmt.Sprintf(`
+table: articles
+model: Article
+columns: [{name: id, type: bigint}, {name: title, type: string}, {name: status, type: string}, {name: views, type: integer}]
+scopes: %s`, scopes)
+}
+
+func TestValidateScopes(t *testing.T) {
+	tests := []struct{ name, scopes, err string }{
+		{"valide", `
+  - {name: published, conditions: [{column: status, value: published}]}
+  - {name: popular, parameters: [{name: min, type: integer}], conditions: [{column: views, operator: ">=", param: min}]}
+  - {name: visible, conditions: [{column: status, operator: in, value: [published, archived]}, {column: title, operator: is not null}]}
+  - {name: recent, conditions: [{sql: "created_at > now() - interval '7 days'"}]}`, ""},
+		{"sans nom", `[{conditions: [{column: status, value: x}]}]`, "doit définir name"},
+		{"dupliqué", `[{name: a, conditions: [{column: status, value: x}]}, {name: a, conditions: [{column: status, value: y}]}]`, "scope dupliqué: a"},
+		{"sans condition", `[{name: empty}]`, "au moins une condition"},
+		{"colonne inconnue", `[{name: a, conditions: [{column: missing, value: x}]}]`, "colonne inconnue dans le scope a: missing"},
+		{"opérateur inconnu", `[{name: a, conditions: [{column: views, operator: "~", value: 1}]}]`, "opérateur inconnu"},
+		{"paramètre non déclaré", `[{name: a, conditions: [{column: views, param: min}]}]`, "paramètre non déclaré min"},
+		{"paramètre de type inconnu", `[{name: a, parameters: [{name: min, type: blob}], conditions: [{column: views, param: min}]}]`, "paramètre invalide"},
+		{"paramètre avec in", `[{name: a, parameters: [{name: s, type: string}], conditions: [{column: status, operator: in, param: s}]}]`, "attend une liste de valeurs"},
+		{"liste sans in", `[{name: a, conditions: [{column: status, value: [a, b]}]}]`, "n'est acceptée qu'avec in"},
+		{"sans valeur", `[{name: a, conditions: [{column: status}]}]`, "doit définir value ou param"},
+		{"paramètre page", `[{name: a, parameters: [{name: page, type: integer}], conditions: [{column: views, param: page}]}]`, "nom de paramètre réservé"},
+		{"paramètre db", `[{name: a, parameters: [{name: db, type: string}], conditions: [{column: status, param: db}]}]`, "nom de paramètre réservé"},
+		{"paramètre ctx", `[{name: a, parameters: [{name: ctx, type: string}], conditions: [{column: status, param: ctx}]}]`, "nom de paramètre réservé"},
+		{"paramètre cursor", `[{name: a, parameters: [{name: cursor, type: string}], conditions: [{column: status, param: cursor}]}]`, "nom de paramètre réservé"},
+	}
+	for _, tt := range tests {
+		_, err := parse(t, scopeSchema(tt.scopes))
+		check(t, tt.name, err, tt.err)
+	}
+}
+
+// Un scope devient une méthode du repository: il ne peut pas porter le nom d'une
+// méthode générée
+func TestReservedScopeNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		reserved bool
+	}{
+		{"create", true},
+		{"update", true},
+		{"delete", true},
+		{"find_all", true},
+		{"find_by_id", true},
+		{"find_by_title", true},
+		{"restore", true},
+		{"force_delete", true},
+		{"find_trashed", true},
+		{"ancestors", true},
+		{"descendants", true},
+		{"history", true},
+		{"find_recent", true},
+		{"with_context", true},
+		{"search", true},
+		{"published", false},
+		{"created_recently", false},
+		{"deleted", false},
+	}
+	for _, tt := range tests {
+		_, err := parse(t, scopeSchema(fmt.Sprintf(`[{name: %s, conditions: [{column: status, value: x}]}]`, tt.name)))
+		want := ""
+		if tt.reserved {
+			want = "est réservé aux méthodes du repository"
+		}
+		check(t, tt.name, err, want)
+	}
+}
@@ -0,0 +1,35 @@
+package parser
+
+import "fmt"
+
+// SearchableColumns retourne les colonnes searchable: true, dans l'ordre du schéma
+func (s *Schema) SearchableColumns() []Column {
+	var columns []Column
+	for _, col := range s.Columns {
+		if col.Searchable {
+			columns = append(columns, col)
+		}
+	}
+	return columns
+}
+
+// validateSearch vérifie que les colonnes de la recherche plein texte sont textuelles et,
+// en SQLite, que la table a une clé primaire entière unique: les tables FTS5 référencent
+// les lignes par leur rowid
+func validateSearch(schema *Schema) error {
+	for _, col := range schema.Columns {
+		if !col.Searchable {
+			continue
+		}
+		if col.FilterKind() != FilterString || col.Type == "uuid" || col.Type == "time" {
+			return fmt.Errorf("la colonne %s de type %s ne peut pas être searchable", col.Name, col.Type)
+		}
+	}
+	if projectDialect == DialectSQLite && len(schema.SearchableColumns()) > 0 {
+		keys := schema.PrimaryKeys()
+		if len(keys) != 1 || keys[0].FilterKind() != FilterInteger {
+			return fmt.Errorf("la recherche plein texte SQLite (searchable) exige une clé primaire entière unique")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package parser
+
+import "testing"
+
+func TestValidateSearch(t *testing.T) {
+	defer SetDialect(DialectPostgres)
+
+	tests := []struct {
+		name    string
+		dialect string
+		schema  string
+		err     string
+	}{
+		{"colonnes textuelles", DialectPostgres, `
+table: articles
+model: Article
+columns: [{name: id, type: bigint}, {name: title, type: string, searchable: true}, {name: body, type: text, searchable: true}]`, ""},
+		{"colonne entière", DialectPostgres, `
+table: articles
+model: Article
+columns: [{name: id, type: bigint}, {name: views, type: integer, searchable: true}]`, "ne peut pas être searchable"},
+		{"colonne uuid", DialectMySQL, `
+table: articles
+model: Article
+columns: [{name: id, type: bigint}, {name: ref, type: uuid, searchable: true}]`, "ne peut pas être searchable"},
+		{"clé uuid en PostgreSQL", DialectPostgres, `
+table: articles
+model: Article
+columns: [{name: id, type: uuid, primary: true}, {name: title, type: string, searchable: true}]`, ""},
+		{"clé uuid en MySQL", DialectMySQL, `
+table: articles
+model: Article
+columns: [{name: id, type: uuid, primary: true}, {name: title, type: string, searchable: true}]`, ""},
+		{"clé entière en SQLite", DialectSQLite, `
+table: articles
+model: Article
+columns: [{name: id, type: bigint}, {name: title, type: string, searchable: true}]`, ""},
+		{"clé uuid en SQLite", DialectSQLite, `
+table: articles
+model: Article
+columns: [{name: id, type: uuid, primary: true}, {name: title, type: string, searchable: true}]`, "exige une clé primaire entière unique"},
+		{"clé composite en SQLite", DialectSQLite, `
+table: order_lines
+model: OrderLine
+columns: [{name: order_id, type: bigint, primary: true}, {name: line_no, type: integer, primary: true}, {name: label, type: string, searchable: true}]`, "exige une clé primaire entière unique"},
+		{"clé uuid en SQLite sans recherche", DialectSQLite, `
+table: articles
+model: Article
+columns: [{name: id, type: uuid, primary: true}, {name: title, type: string}]`, ""},
+	}
+	for _, tt := range tests {
+		SetDialect(tt.dialect)
+		_, err := parse(t, tt.schema)
+		check(t, tt.name, err, tt.err)
+	}
+}
@@ -0,0 +1,58 @@
+package parser
+
+import "fmt"
+
+// tenantColumn est la colonne de tenant des schémas tenant_scoped, commune au projet
+var tenantColumn = "tenant_id"
+
+// SetTenantColumn définit la colonne de tenant du projet
+func SetTenantColumn(name string) {
+	if name != "" {
+		tenantColumn = name
+	}
+}
+
+// TenantColumn retourne la colonne de tenant du projet
+func TenantColumn() string {
+	return tenantColumn
+}
+
+// expandTenant ajoute la colonne de tenant d'un schéma tenant_scoped si elle n'est pas
+// déclarée, la marque comme colonne de tenant et l'indexe
+func expandTenant(schema *Schema) {
+	if !schema.TenantScoped {
+		return
+	}
+	if !schema.HasColumn(tenantColumn) {
+		schema.Columns = append(schema.Columns, Column{
+			Name:    tenantColumn,
+			Type:    "string",
+			Size:    100,
+			Comment: "Tenant propriétaire de l'enregistrement",
+		})
+	}
+	for i := range schema.Columns {
+		if schema.Columns[i].Name == tenantColumn {
+			schema.Columns[i].Tenant = true
+		}
+	}
+	for _, idx := range schema.Indexes {
+		if len(idx.Columns) > 0 && idx.Columns[0] == tenantColumn {
+			return
+		}
+	}
+	schema.Indexes = append(schema.Indexes, Index{
+		Name:    "idx_" + schema.Table + "_" + tenantColumn,
+		Columns: []string{tenantColumn},
+	})
+}
+
+// validateTenant vérifie la colonne de tenant: non nullable et hors clé primaire
+func validateTenant(schema *Schema) error {
+	for _, col := range schema.Columns {
+		if col.Tenant && (col.Nullable || col.Primary) {
+			return fmt.Errorf("la colonne %s d'un schéma tenant_scoped doit être non nullable et hors clé primaire", col.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package parser
+
+import "testing"
+
+func TestTenantColumn(t *testing.T) {
+	defer SetTenantColumn("tenant_id")
+
+	tests := []struct {
+		name    string
+		column  string
+		schema  string
+		typ     string
+		indexes int
+		err     string
+	}{
+		{"colonne ajoutée et indexée", "tenant_id", `
+table: articles
+model: Article
+tenant_scoped: true
+columns: [{name: id, type: bigint}]`, "string", 1, ""},
+		{"colonne du projet", "account_id", `
+table: articles
+model: Article
+tenant_scoped: true
+columns: [{name: id, type: bigint}]`, "string", 1, ""},
+		{"colonne déclarée et déjà indexée", "tenant_id", `
+table: articles
+model: Article
+tenant_scoped: true
+columns: [{name: id, type: bigint}, {name: tenant_id, type: bigint}]
+indexes: [{name: idx_articles_tenant_slug, columns: [tenant_id, id], unique: true}]`, "bigint", 1, ""},
+		{"sans tenant_scoped", "tenant_id", `
+table: articles
+model: Article
+columns: [{name: id, type: bigint}]`, "", 0, ""},
+		{"colonne nullable", "tenant_id", `
+table: articles
+model: Article
+tenant_scoped: true
+columns: [{name: id, type: bigint}, {name: tenant_id, type: string, nullable: true}]`, "", 0, "doit être non nullable et hors clé primaire"},
+		{"colonne de la clé primaire", "tenant_id", `
+table: articles
+model: Article
+tenant_scoped: true
+columns: [{name: tenant_id, type: string, primary: true}, {name: slug, type: string, primary: true}]`, "", 0, "doit être non nullable et hors clé primaire"},
+	}
+	for _, tt := range tests {
+		SetTenantColumn(tt.column)
+		schema, err := parse(t, tt.schema)
+		check(t, tt.name, err, tt.err)
+		if err != nil {
+			continue
+		}
+		var typ string
+		for _, col := range schema.Columns {
+			if col.Tenant {
+				if col.Name != tt.column {
+					t.Errorf("%s: colonne de tenant = %s, want %s", tt.name, col.Name, tt.column)
+				}
+				typ = col.Type
+			}
+		}
+		if typ != tt.typ || len(schema.Indexes) != tt.indexes {
+			t.Errorf("%s: colonne de tenant %q, %d index, want %q, %d index", tt.name, typ, len(schema.Indexes), tt.typ, tt.indexes)
+		}
+	}
+}
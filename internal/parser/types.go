@@ -0,0 +1,153 @@
+package parser
+
+import "strings"
+
+// TypeDefinition décrit un type de colonne personnalisé (email, phone, money, ...)
+type TypeDefinition struct {
+	GoType     string            `yaml:"go_type"`    // Type Go du champ (ex: string, decimal.Decimal)
+	Import     string            `yaml:"import"`     // Import nécessaire au type Go (optionnel)
+	DBTypes    map[string]string `yaml:"db_types"`   // Type SQL par dialecte (postgres, mysql, sqlite, default)
+	Validation string            `yaml:"validation"` // Tags de validation par défaut (ex: "email")
+	JSON       string            `yaml:"json"`       // Représentation JSON: "" (native) ou "string"
+}
+
+// Styles de représentation des colonnes nullables
+const (
+	NullablePointer = "pointer" // *T (par défaut)
+	NullableSQL     = "sql"     // sql.NullString, sql.NullInt64, ... (sql.Null[T] à défaut)
+	NullableGeneric = "generic" // types.Null[T] généré dans app/types
+)
+
+// nullableStyle est le style utilisé pour les colonnes nullables
+var nullableStyle = NullablePointer
+
+// SetNullableStyle définit le style des colonnes nullables
+func SetNullableStyle(style string) {
+	if style != "" {
+		nullableStyle = style
+	}
+}
+
+// NullableStyle retourne le style des colonnes nullables
+func NullableStyle() string {
+	return nullableStyle
+}
+
+// sqlNullType retourne le type database/sql (et son champ valeur) pour un type Go
+func sqlNullType(goType string) (string, string) {
+	switch goType {
+	case "string":
+		return "sql.NullString", "String"
+	case "int64":
+		return "sql.NullInt64", "Int64"
+	case "int32":
+		return "sql.NullInt32", "Int32"
+	case "int16":
+		return "sql.NullInt16", "Int16"
+	case "float64":
+		return "sql.NullFloat64", "Float64"
+	case "bool":
+		return "sql.NullBool", "Bool"
+	case "time.Time":
+		return "sql.NullTime", "Time"
+	case "decimal.Decimal":
+		return "decimal.NullDecimal", "Decimal"
+	}
+	return "sql.Null[" + goType + "]", "V"
+}
+
+// DBType retourne le type SQL pour un dialecte donné
+func (t TypeDefinition) DBType(dialect string) string {
+	if dbType, ok := t.DBTypes[dialect]; ok {
+		return dbType
+	}
+	return t.DBTypes["default"]
+}
+
+// baseTypes contient les types natifs supportés par le générateur
+var baseTypes = map[string]string{
+	"string":    "string",
+	"text":      "string",
+	"int":       "int",
+	"integer":   "int",
+	"bigint":    "int64",
+	"smallint":  "int16",
+	"float":     "float64",
+	"double":    "float64",
+	"decimal":   "decimal.Decimal",
+	"money":     "decimal.Decimal",
+	"boolean":   "bool",
+	"bool":      "bool",
+	"date":      "time.Time",
+	"datetime":  "time.Time",
+	"timestamp": "time.Time",
+	"time":      "time.Time",
+	"uuid":      "string",
+	"json":      "string",
+	"jsonb":     "string",
+}
+
+// customTypes contient les types personnalisés enregistrés
+var customTypes = map[string]TypeDefinition{
+	"email": {
+		GoType:     "string",
+		DBTypes:    map[string]string{"default": "varchar(255)"},
+		Validation: "email",
+	},
+	"url": {
+		GoType:     "string",
+		DBTypes:    map[string]string{"default": "varchar(2048)"},
+		Validation: "url",
+	},
+	"phone": {
+		GoType:     "string",
+		DBTypes:    map[string]string{"default": "varchar(20)"},
+		Validation: "e164",
+	},
+	"currency": {
+		GoType:     "string",
+		DBTypes:    map[string]string{"sqlite": "text", "default": "char(3)"},
+		Validation: "iso4217",
+	},
+	"ulid": {
+		GoType:     "string",
+		DBTypes:    map[string]string{"sqlite": "text", "default": "char(26)"},
+		Validation: "ulid",
+	},
+	"ip": {
+		GoType:     "string",
+		DBTypes:    map[string]string{"postgres": "inet", "default": "varchar(45)"},
+		Validation: "ip",
+	},
+}
+
+// RegisterType enregistre (ou remplace) un type de colonne personnalisé
+func RegisterType(name string, def TypeDefinition) {
+	customTypes[name] = def
+}
+
+// LookupType retourne la définition d'un type personnalisé
+func LookupType(name string) (TypeDefinition, bool) {
+	def, ok := customTypes[name]
+	return def, ok
+}
+
+// IsKnownType indique si un type de colonne est supporté
+func IsKnownType(name string) bool {
+	if _, ok := baseTypes[name]; ok {
+		return true
+	}
+	_, ok := customTypes[name]
+	return ok
+}
+
+// importFor retourne l'import nécessaire à un type Go natif
+func importFor(goType string) string {
+	if strings.Contains(goType, "time.Time") {
+		return "time"
+	}
+	if strings.Contains(goType, "decimal.Decimal") {
+		return "github.com/shopspring/decimal"
+	}
+	return ""
+}
@@ -0,0 +1,159 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCustomTypes(t *testing.T) {
+	RegisterType("sku", TypeDefinition{
+		GoType:     "string",
+		DBTypes:    map[string]string{"postgres": "citext", "default": "varchar(32)"},
+		Validation: "alphanum",
+		JSON:       "string",
+	})
+	defer delete(customTypes, "sku")
+
+	tests := []struct {
+		col     Column
+		dialect string
+		goType  string
+		dbType  string
+		tag     string
+	}{
+		{Column{Name: "contact", Type: "email"}, DialectPostgres, "string", "varchar(255)", `validate:"required,email"`},
+		{Column{Name: "site", Type: "url"}, DialectMySQL, "string", "varchar(2048)", `validate:"required,url"`},
+		{Column{Name: "address", Type: "ip"}, DialectPostgres, "string", "inet", `validate:"required,ip"`},
+		{Column{Name: "address", Type: "ip"}, DialectSQLite, "string", "varchar(45)", `validate:"required,ip"`},
+		{Column{Name: "code", Type: "sku"}, DialectPostgres, "string", "citext", `validate:"required,alphanum"`},
+		{Column{Name: "code", Type: "sku"}, DialectMySQL, "string", "varchar(32)", `validate:"required,alphanum"`},
+		{Column{Name: "title", Type: "string", Size: 120}, DialectMySQL, "string", "varchar(120)", `validate:"required,max=120"`},
+		{Column{Name: "title", Type: "string", Size: 120}, DialectSQLite, "string", "text", `validate:"required,max=120"`},
+	}
+	for _, tt := range tests {
+		if got := tt.col.GetGoType(); got != tt.goType {
+			t.Errorf("GetGoType(%s) = %q, want %q", tt.col.Type, got, tt.goType)
+		}
+		if got := tt.col.GetDBType(tt.dialect); got != tt.dbType {
+			t.Errorf("GetDBType(%s, %s) = %q, want %q", tt.col.Type, tt.dialect, got, tt.dbType)
+		}
+		if got := tt.col.GetValidationTag(); got != tt.tag {
+			t.Errorf("GetValidationTag(%s) = %q, want %q", tt.col.Type, got, tt.tag)
+		}
+	}
+
+	if !IsKnownType("sku") || IsKnownType("blob") {
+		t.Errorf("IsKnownType: sku doit être connu, blob inconnu")
+	}
+	col := Column{Name: "code", Type: "sku"}
+	if got := col.GetJSONTag(); got != `json:"code,string"` {
+		t.Errorf("GetJSONTag(sku) = %q, want %q", got, `json:"code,string"`)
+	}
+}
+
+func TestDecimalTypes(t *testing.T) {
+	tests := []struct {
+		col     Column
+		dialect string
+		dbType  string
+	}{
+		{Column{Type: "decimal", Precision: 10, Scale: 2}, DialectPostgres, "numeric(10,2)"},
+		{Column{Type: "decimal", Precision: 10, Scale: 2}, DialectMySQL, "decimal(10,2)"},
+		{Column{Type: "decimal"}, DialectPostgres, "numeric"},
+		{Column{Type: "money", Precision: 19, Scale: 4}, DialectSQLite, "numeric(19,4)"},
+	}
+	for _, tt := range tests {
+		if got := tt.col.GetGoType(); got != "decimal.Decimal" {
+			t.Errorf("GetGoType(%s) = %q, want decimal.Decimal", tt.col.Type, got)
+		}
+		if got := tt.col.GetDBType(tt.dialect); got != tt.dbType {
+			t.Errorf("GetDBType(%s, %s) = %q, want %q", tt.col.Type, tt.dialect, got, tt.dbType)
+		}
+	}
+}
+
+func TestNullableStyles(t *testing.T) {
+	defer SetNullableStyle(NullablePointer)
+
+	tests := []struct {
+		style   string
+		col     Column
+		goType  string
+		fromPtr string
+		zero    string
+		imports string
+	}{
+		{NullablePointer, Column{Type: "string", Nullable: true}, "*string", "p", "nil", ""},
+		{NullablePointer, Column{Type: "timestamp", Nullable: true}, "*time.Time", "p", "nil", "time"},
+		{NullableSQL, Column{Type: "string", Nullable: true}, "sql.NullString", "sql.NullString{String: *p, Valid: true}", "sql.NullString{}", "database/sql"},
+		{NullableSQL, Column{Type: "bigint", Nullable: true}, "sql.NullInt64", "sql.NullInt64{Int64: *p, Valid: true}", "sql.NullInt64{}", "database/sql"},
+		{NullableSQL, Column{Type: "decimal", Nullable: true}, "decimal.NullDecimal", "decimal.NullDecimal{Decimal: *p, Valid: true}", "decimal.NullDecimal{}", "github.com/shopspring/decimal"},
+		{NullableSQL, Column{Type: "integer", Nullable: true}, "sql.Null[int]", "sql.Null[int]{V: *p, Valid: true}", "sql.Null[int]{}", "database/sql"},
+		{NullableGeneric, Column{Type: "boolean", Nullable: true}, "types.Null[bool]", "types.NewNull(*p)", "types.Null[bool]{}", "app/types"},
+		{NullableGeneric, Column{Type: "string"}, "string", "", "", ""},
+	}
+	for _, tt := range tests {
+		SetNullableStyle(tt.style)
+		col := tt.col
+		if got := col.GetGoType(); got != tt.goType {
+			t.Errorf("%s: GetGoType(%s) = %q, want %q", tt.style, col.Type, got, tt.goType)
+		}
+		if got := strings.Join(col.GetImports(), ","); got != tt.imports {
+			t.Errorf("%s: GetImports(%s) = %q, want %q", tt.style, col.Type, got, tt.imports)
+		}
+		if !col.Nullable {
+			continue
+		}
+		if got := col.NullFromPtr("p"); got != tt.fromPtr {
+			t.Errorf("%s: NullFromPtr(%s) = %q, want %q", tt.style, col.Type, got, tt.fromPtr)
+		}
+		if got := col.NullZero(); got != tt.zero {
+			t.Errorf("%s: NullZero(%s) = %q, want %q", tt.style, col.Type, got, tt.zero)
+		}
+	}
+}
+
+func TestValidationTags(t *testing.T) {
+	tests := []struct {
+		col  Column
+		want string
+	}{
+		// false et 0 sont des valeurs légitimes
+		{Column{Name: "published", Type: "boolean"}, ""},
+		{Column{Name: "views", Type: "integer"}, ""},
+		{Column{Name: "title", Type: "string", Size: 255}, `validate:"required,max=255"`},
+		{Column{Name: "subtitle", Type: "string", Size: 255, Nullable: true}, `validate:"omitempty,max=255"`},
+		{Column{Name: "created_at", Type: "timestamp"}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.col.GetValidationTag(); got != tt.want {
+			t.Errorf("GetValidationTag(%s) = %q, want %q", tt.col.Name, got, tt.want)
+		}
+	}
+}
+
+func TestNullExpressions(t *testing.T) {
+	defer SetNullableStyle(NullablePointer)
+
+	tests := []struct {
+		style string
+		typ   string
+		check string
+		value string
+	}{
+		{NullablePointer, "bigint", "v == nil", "*v"},
+		{NullableSQL, "bigint", "!v.Valid", "v.Int64"},
+		{NullableSQL, "uuid", "!v.Valid", "v.String"},
+		{NullableGeneric, "bigint", "!v.Valid", "v.V"},
+	}
+	for _, tt := range tests {
+		SetNullableStyle(tt.style)
+		col := Column{Type: tt.typ, Nullable: true}
+		if got := col.NullCheck("v"); got != tt.check {
+			t.Errorf("%s: NullCheck(%s) = %q, want %q", tt.style, tt.typ, got, tt.check)
+		}
+		if got := col.NullValue("v"); got != tt.value {
+			t.Errorf("%s: NullValue(%s) = %q, want %q", tt.style, tt.typ, got, tt.value)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package parser
+
+import "fmt"
+
+// VersionColumn est la colonne de verrouillage optimiste d'un schéma versioned
+const VersionColumn = "version"
+
+// expandVersion ajoute la colonne version d'un schéma versioned si elle n'est pas déclarée
+// et la marque comme colonne de version. Une colonne version sans versioned: true reste
+// une colonne ordinaire.
+func expandVersion(schema *Schema) {
+	if !schema.Versioned {
+		return
+	}
+	if !schema.HasColumn(VersionColumn) {
+		schema.Columns = append(schema.Columns, Column{
+			Name:    VersionColumn,
+			Type:    "bigint",
+			Default: 1,
+			Comment: "Version de l'enregistrement (verrouillage optimiste)",
+		})
+	}
+	for i := range schema.Columns {
+		if schema.Columns[i].Name == VersionColumn {
+			schema.Columns[i].Version = true
+		}
+	}
+}
+
+// VersionField retourne la colonne de version d'un schéma versioned
+func (s *Schema) VersionField() (Column, bool) {
+	for _, col := range s.Columns {
+		if col.Version {
+			return col, true
+		}
+	}
+	return Column{}, false
+}
+
+// validateVersion vérifie la colonne de version: entier non nullable hors clé primaire
+func validateVersion(schema *Schema) error {
+	col, ok := schema.VersionField()
+	if !ok {
+		return nil
+	}
+	if col.Nullable || col.Primary || (col.Type != "integer" && col.Type != "bigint") {
+		return fmt.Errorf("la colonne %s d'un schéma versioned doit être un integer ou bigint non nullable hors clé primaire", VersionColumn)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package parser
+
+import "testing"
+
+func TestVersionColumn(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		typ    string
+		err    string
+	}{
+		{"colonne ajoutée", `
+table: articles
+model: Article
+versioned: true
+columns: [{name: id, type: bigint}]`, "bigint", ""},
+		{"colonne déclarée", `
+table: articles
+model: Article
+versioned: true
+columns: [{name: id, type: bigint}, {name: version, type: integer}]`, "integer", ""},
+		{"colonne ordinaire sans versioned", `
+table: articles
+model: Article
+columns: [{name: id, type: bigint}, {name: version, type: string}]`, "", ""},
+		{"colonne nullable", `
+table: articles
+model: Article
+versioned: true
+columns: [{name: id, type: bigint}, {name: version, type: integer, nullable: true}]`, "", "doit être un integer ou bigint non nullable"},
+		{"colonne texte", `
+table: articles
+model: Article
+versioned: true
+columns: [{name: id, type: bigint}, {name: version, type: string}]`, "", "doit être un integer ou bigint non nullable"},
+	}
+	for _, tt := range tests {
+		schema, err := parse(t, tt.schema)
+		check(t, tt.name, err, tt.err)
+		if err != nil {
+			continue
+		}
+		col, ok := schema.VersionField()
+		if ok != (tt.typ != "") || col.Type != tt.typ {
+			t.Errorf("%s: VersionField() = %q (%v), want %q", tt.name, col.Type, ok, tt.typ)
+		}
+	}
+}